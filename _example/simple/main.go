@@ -7,6 +7,7 @@ import (
 	"net/http"
 
 	"github.com/Alkemic/go-route"
+	"github.com/Alkemic/go-route/server"
 )
 
 var (
@@ -54,5 +55,9 @@ func main() {
 	routes.Add(`^/(?P<param>.*)/$`, view)
 	routes.NotFound = getHandle404("main")
 
-	log.Fatalln(http.ListenAndServe(*bindAddr, routes))
+	srv := server.New(*bindAddr, routes)
+	routes.Add(`^/ready$`, srv.ReadinessHandler)
+	if err := srv.ListenAndServe(); err != nil {
+		log.Fatalln(err)
+	}
 }
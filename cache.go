@@ -0,0 +1,161 @@
+package route
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+)
+
+// routesGeneration is bumped whenever routes of any router change, cached resolutions from older generations
+// are discarded.
+var routesGeneration uint64
+
+func routesChanged() {
+	atomic.AddUint64(&routesGeneration, 1)
+}
+
+// hop is resolution made by single router while handling request.
+type hop struct {
+	index   int
+	match   []string
+	version string
+	urlPath string
+}
+
+type cacheKey struct {
+	host   string
+	method string
+	path   string
+}
+
+type cacheEntry struct {
+	key        cacheKey
+	hops       []hop
+	generation uint64
+}
+
+// CacheStats holds statistics of router's match cache.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+	Size   int
+}
+
+// matchCache is bounded LRU cache mapping host, method and path into resolved chain of routes.
+type matchCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[cacheKey]*list.Element
+	lru     *list.List
+	hits    uint64
+	misses  uint64
+}
+
+func newMatchCache(size int) *matchCache {
+	return &matchCache{
+		size:    size,
+		entries: map[cacheKey]*list.Element{},
+		lru:     list.New(),
+	}
+}
+
+func (c *matchCache) get(key cacheKey) ([]hop, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		entry := el.Value.(*cacheEntry)
+		if entry.generation == atomic.LoadUint64(&routesGeneration) {
+			c.lru.MoveToFront(el)
+			c.hits++
+			return entry.hops, true
+		}
+		c.lru.Remove(el)
+		delete(c.entries, key)
+	}
+	c.misses++
+	return nil, false
+}
+
+func (c *matchCache) add(key cacheKey, hops []hop, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		el.Value = &cacheEntry{key: key, hops: hops, generation: generation}
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key: key, hops: hops, generation: generation})
+	for c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+func (c *matchCache) stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{Hits: c.hits, Misses: c.misses, Size: c.lru.Len()}
+}
+
+// prepare sets up request's match state to replay cached resolution, or to record a new one.
+func (c *matchCache) prepare(state *matchState, key cacheKey) {
+	generation := atomic.LoadUint64(&routesGeneration)
+	if hops, ok := c.get(key); ok {
+		state.replay = hops
+		return
+	}
+	state.cache = c
+	state.cacheKey = key
+	state.generation = generation
+}
+
+func (s *matchState) replayHop() (hop, bool) {
+	if s == nil || len(s.replay) == 0 {
+		return hop{}, false
+	}
+	h := s.replay[0]
+	s.replay = s.replay[1:]
+	return h, true
+}
+
+func (s *matchState) recordHop(h hop) {
+	if s != nil {
+		s.hops = append(s.hops, h)
+	}
+}
+
+func (s *matchState) markUncacheable() {
+	if s != nil {
+		s.uncacheable = true
+	}
+}
+
+// commit stores recorded resolution in cache, it's called once request reaches its final handler.
+func (s *matchState) commit() {
+	if s == nil || s.cache == nil || s.uncacheable {
+		return
+	}
+	s.cache.add(s.cacheKey, s.hops, s.generation)
+	s.cache = nil
+}
+
+// EnableCache enables bounded LRU cache of resolved routes, a cache hit skips all regexp evaluation. Only
+// resolutions that don't depend on headers are cached, and cache is invalidated when routes change. Cache is
+// used only when router serves requests directly, not as a sub router. Size of 0 or less disables the cache.
+func (r *RegexpRouter) EnableCache(size int) *RegexpRouter {
+	if size <= 0 {
+		r.cache = nil
+		return r
+	}
+	r.cache = newMatchCache(size)
+	return r
+}
+
+// CacheStats returns statistics of match cache, will be empty if cache isn't enabled.
+func (r *RegexpRouter) CacheStats() CacheStats {
+	if r.cache == nil {
+		return CacheStats{}
+	}
+	return r.cache.stats()
+}
@@ -0,0 +1,92 @@
+package route
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMatchCache(t *testing.T) {
+	view := func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s %s", GetParams(r)["pk"], GetVersion(r))
+	}
+	newsRoutes := New().Add(`^/(?P<pk>\d+)$`, view, http.MethodGet)
+	versions := NewVersionRouter("v1").Add("v1", New().Add(`^/(?P<pk>\d+)$`, view))
+	routing := New().
+		Add(`^/news`, newsRoutes).
+		Add(`^/api`, versions).
+		EnableCache(2)
+
+	serve := func(method, path string, headers ...string) string {
+		req := httptest.NewRequest(method, "http://example.com"+path, nil)
+		for i := 0; i < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		w := httptest.NewRecorder()
+		routing.ServeHTTP(w, req)
+		body, _ := ioutil.ReadAll(w.Result().Body)
+		return fmt.Sprintf("%d %s", w.Code, body)
+	}
+	hasStats := func(hits, misses uint64, size int) {
+		t.Helper()
+		expected := CacheStats{Hits: hits, Misses: misses, Size: size}
+		if stats := routing.CacheStats(); stats != expected {
+			t.Errorf("Expected cache stats '%+v', got '%+v'", expected, stats)
+		}
+	}
+	hasResponse := func(expected, got string) {
+		t.Helper()
+		if expected != got {
+			t.Errorf("Expected response '%s', got '%s'", expected, got)
+		}
+	}
+
+	hasResponse("200 12 ", serve(http.MethodGet, "/news/12"))
+	hasStats(0, 1, 1)
+	hasResponse("200 12 ", serve(http.MethodGet, "/news/12"))
+	hasStats(1, 1, 1)
+
+	hasResponse("405 Method Not Allowed\n", serve(http.MethodPost, "/news/12"))
+	hasResponse("404 404 page not found\n", serve(http.MethodGet, "/blog/12"))
+	hasStats(1, 3, 1)
+
+	hasResponse("200 7 v1", serve(http.MethodGet, "/api/v1/7"))
+	hasResponse("200 7 v1", serve(http.MethodGet, "/api/v1/7"))
+	hasStats(2, 4, 2)
+
+	hasResponse("200 8 v1", serve(http.MethodGet, "/api/8", "X-API-Version", "1"))
+	hasResponse("200 8 v1", serve(http.MethodGet, "/api/8", "X-API-Version", "1"))
+	hasStats(2, 6, 2)
+
+	hasResponse("200 13 ", serve(http.MethodGet, "/news/13"))
+	hasStats(2, 7, 2)
+	hasResponse("200 12 ", serve(http.MethodGet, "/news/12"))
+	hasStats(2, 8, 2)
+
+	newsRoutes.Add(`^/latest$`, view)
+	hasResponse("200 12 ", serve(http.MethodGet, "/news/12"))
+	hasStats(2, 9, 2)
+}
+
+func TestMatchCacheDisabled(t *testing.T) {
+	for _, size := range []int{0, -1} {
+		t.Run(fmt.Sprint(size), func(t *testing.T) {
+			routing := New().
+				Add(`^/news$`, func(w http.ResponseWriter, r *http.Request) {}).
+				EnableCache(100).
+				EnableCache(size)
+			w := httptest.NewRecorder()
+
+			routing.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example.com/news", nil))
+
+			if w.Code != http.StatusOK {
+				t.Errorf("Expected status code '%d', but got '%d'", http.StatusOK, w.Code)
+			}
+			if stats := routing.CacheStats(); stats != (CacheStats{}) {
+				t.Errorf("Expected empty cache stats of disabled cache, got '%+v'", stats)
+			}
+		})
+	}
+}
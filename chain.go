@@ -0,0 +1,59 @@
+package route
+
+import "net/http"
+
+// Chain is ordered list of middlewares, the first one added runs first, i.e. it's the outermost one.
+type Chain struct {
+	middlewares []Middleware
+}
+
+// NewChain returns chain of given middlewares, they run in order they are passed.
+func NewChain(middlewares ...Middleware) Chain {
+	return Chain{}.Append(middlewares...)
+}
+
+// Append returns new chain with middlewares added at the end, they run after the ones already in chain.
+func (c Chain) Append(middlewares ...Middleware) Chain {
+	mws := make([]Middleware, 0, len(c.middlewares)+len(middlewares))
+	mws = append(mws, c.middlewares...)
+	return Chain{middlewares: append(mws, middlewares...)}
+}
+
+// Prepend returns new chain with middlewares added at the beginning, they run before the ones already in chain.
+func (c Chain) Prepend(middlewares ...Middleware) Chain {
+	mws := make([]Middleware, 0, len(c.middlewares)+len(middlewares))
+	mws = append(mws, middlewares...)
+	return Chain{middlewares: append(mws, c.middlewares...)}
+}
+
+// ThenFunc wraps fn with middlewares of the chain.
+func (c Chain) ThenFunc(fn http.HandlerFunc) http.HandlerFunc {
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		fn = c.middlewares[i](fn)
+	}
+	return fn
+}
+
+// Then wraps handler with middlewares of the chain.
+func (c Chain) Then(handler http.Handler) http.Handler {
+	return c.ThenFunc(handler.ServeHTTP)
+}
+
+// Middleware returns the whole chain as single middleware, i.e. to be added to router with AddMiddleware.
+func (c Chain) Middleware() Middleware {
+	return c.ThenFunc
+}
+
+// FromStandard adapts middleware in form of func(http.Handler) http.Handler.
+func FromStandard(mw func(http.Handler) http.Handler) Middleware {
+	return func(fn http.HandlerFunc) http.HandlerFunc {
+		return mw(fn).ServeHTTP
+	}
+}
+
+// Standard adapts middleware into func(http.Handler) http.Handler form.
+func (m Middleware) Standard() func(http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		return m(handler.ServeHTTP)
+	}
+}
@@ -0,0 +1,79 @@
+package route
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func tagMiddleware(tag string) Middleware {
+	return func(fn http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, tag)
+			fn(w, r)
+		}
+	}
+}
+
+func TestChain(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "handler")
+	}
+	base := NewChain(tagMiddleware("b"), tagMiddleware("c"))
+	standard := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "s")
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	testCases := []struct {
+		name    string
+		handler http.Handler
+
+		expectedBody string
+	}{
+		{
+			name:         "new chain",
+			handler:      base.ThenFunc(handler),
+			expectedBody: "bchandler",
+		}, {
+			name:         "append",
+			handler:      base.Append(tagMiddleware("d")).ThenFunc(handler),
+			expectedBody: "bcdhandler",
+		}, {
+			name:         "prepend",
+			handler:      base.Prepend(tagMiddleware("a")).Then(http.HandlerFunc(handler)),
+			expectedBody: "abchandler",
+		}, {
+			name:         "empty chain",
+			handler:      NewChain().ThenFunc(handler),
+			expectedBody: "handler",
+		}, {
+			name:         "from standard",
+			handler:      base.Append(FromStandard(standard)).ThenFunc(handler),
+			expectedBody: "bcshandler",
+		}, {
+			name:         "to standard",
+			handler:      tagMiddleware("a").Standard()(base.ThenFunc(handler)),
+			expectedBody: "abchandler",
+		}, {
+			name:         "router middleware",
+			handler:      New().Add(`^/$`, handler).AddMiddleware(base.Middleware()).AddMiddleware(tagMiddleware("a")),
+			expectedBody: "abchandler",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+			w := httptest.NewRecorder()
+
+			tc.handler.ServeHTTP(w, req)
+
+			if tc.expectedBody != w.Body.String() {
+				t.Errorf("Expected body '%s', but got '%s'", tc.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Alkemic/go-route"
+)
+
+// builder is URL builder function generated for named route.
+type builder struct {
+	funcName string
+	route    string
+	source   string
+	path     string
+	params   []route.PathParam
+}
+
+// generateURLs returns Go source with URL builder function for every named route in table, values of params are
+// validated and escaped.
+func generateURLs(entries []entry, pkgName string) ([]byte, error) {
+	builders, err := collectBuilders(entries, nil, map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+	if len(builders) == 0 {
+		return nil, errors.New("no named routes found")
+	}
+
+	var (
+		body       bytes.Buffer
+		vars       bytes.Buffer
+		useStrconv bool
+	)
+	for _, b := range builders {
+		var (
+			args  []string
+			parts []string
+		)
+		path := b.path
+		for _, param := range b.params {
+			placeholder := "{" + param.Name + "}"
+			i := strings.Index(path, placeholder)
+			if path[:i] != "" {
+				parts = append(parts, strconv.Quote(path[:i]))
+			}
+			path = path[i+len(placeholder):]
+
+			arg, value := paramIdent(param.Name), paramIdent(param.Name)
+			if param.Integer {
+				args = append(args, arg+" int")
+				value = "strconv.Itoa(" + arg + ")"
+				useStrconv = true
+			} else {
+				args = append(args, arg+" string")
+			}
+			patternVar := "url" + b.funcName[len("URL"):] + exportedName(param.Name)
+			fmt.Fprintf(&vars, "\t%s = regexp.MustCompile(%s)\n", patternVar, strconv.Quote("^(?:"+param.Pattern+")$"))
+			parts = append(parts, fmt.Sprintf("goRouteURLParam(%s, %q, %s)", patternVar, param.Name, value))
+		}
+		if path != "" || len(parts) == 0 {
+			parts = append(parts, strconv.Quote(path))
+		}
+		fmt.Fprintf(&body, "\n// %s returns path of route %q (%s)", b.funcName, b.route, b.source)
+		if len(b.params) > 0 {
+			body.WriteString(", it panics when values don't match route pattern")
+		}
+		body.WriteString(".\n")
+		fmt.Fprintf(&body, "func %s(%s) string {\n\treturn %s\n}\n", b.funcName, strings.Join(args, ", "), strings.Join(parts, " + "))
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by go-route gen; DO NOT EDIT.\n\npackage %s\n", pkgName)
+	if vars.Len() > 0 {
+		src.WriteString("\nimport (\n\t\"fmt\"\n\t\"net/url\"\n\t\"regexp\"\n")
+		if useStrconv {
+			src.WriteString("\t\"strconv\"\n")
+		}
+		src.WriteString(")\n\nvar (\n")
+		src.Write(vars.Bytes())
+		src.WriteString(")\n")
+	}
+	src.Write(body.Bytes())
+	if vars.Len() > 0 {
+		src.WriteString(`
+func goRouteURLParam(re *regexp.Regexp, name, value string) string {
+	if !re.MatchString(value) {
+		panic(fmt.Sprintf("value %q of param %q doesn't match %s", value, name, re))
+	}
+	return url.PathEscape(value)
+}
+`)
+	}
+	return format.Source(src.Bytes())
+}
+
+func collectBuilders(entries []entry, parents []string, funcs map[string]string) ([]builder, error) {
+	var builders []builder
+	for _, e := range entries {
+		patterns := append(append([]string(nil), parents...), e.pattern)
+		if len(e.children) > 0 {
+			children, err := collectBuilders(e.children, patterns, funcs)
+			if err != nil {
+				return nil, err
+			}
+			builders = append(builders, children...)
+			continue
+		}
+		if e.name == "" {
+			continue
+		}
+		path, params, err := route.ParsePathTemplate(patterns...)
+		if err != nil {
+			return nil, fmt.Errorf("%s: can't build URL of route %q: %v", e.source, e.name, err)
+		}
+		seen := map[string]bool{}
+		for _, param := range params {
+			if seen[param.Name] {
+				return nil, fmt.Errorf("%s: can't build URL of route %q: group name %q is used more than once", e.source, e.name, param.Name)
+			}
+			seen[param.Name] = true
+		}
+		funcName := "URL" + exportedName(e.name)
+		if source, ok := funcs[funcName]; ok {
+			return nil, fmt.Errorf("%s: route %q generates function %s already generated for %s", e.source, e.name, funcName, source)
+		}
+		funcs[funcName] = e.source
+		builders = append(builders, builder{funcName: funcName, route: e.name, source: e.source, path: path, params: params})
+	}
+	return builders, nil
+}
+
+// exportedName converts route or group name into exported identifier, i.e. "news-detail" into "NewsDetail".
+func exportedName(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func paramIdent(name string) string {
+	if token.IsKeyword(name) {
+		return name + "Param"
+	}
+	return name
+}
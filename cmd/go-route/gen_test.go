@@ -0,0 +1,111 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestGenerateURLs(t *testing.T) {
+	entries := []entry{
+		{pattern: `^/$`, name: "index", source: "routes[0]"},
+		{pattern: `^/news`, source: "routes[1]", children: []entry{
+			{pattern: `^/(?P<pk>\d+),(?P<slug>[a-z]+)\.html$`, name: "news-detail", source: "routes[1].children[0]"},
+			{pattern: `^/(?P<type>[a-z]+)/$`, name: "news.by_type", source: "routes[1].children[1]"},
+			{pattern: `^/archive$`, source: "routes[1].children[2]"},
+		}},
+	}
+	expected := `// Code generated by go-route gen; DO NOT EDIT.
+
+package web
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+)
+
+var (
+	urlNewsDetailPk   = regexp.MustCompile("^(?:[0-9]+)$")
+	urlNewsDetailSlug = regexp.MustCompile("^(?:[a-z]+)$")
+	urlNewsByTypeType = regexp.MustCompile("^(?:[a-z]+)$")
+)
+
+// URLIndex returns path of route "index" (routes[0]).
+func URLIndex() string {
+	return "/"
+}
+
+// URLNewsDetail returns path of route "news-detail" (routes[1].children[0]), it panics when values don't match route pattern.
+func URLNewsDetail(pk int, slug string) string {
+	return "/news/" + goRouteURLParam(urlNewsDetailPk, "pk", strconv.Itoa(pk)) + "," + goRouteURLParam(urlNewsDetailSlug, "slug", slug) + ".html"
+}
+
+// URLNewsByType returns path of route "news.by_type" (routes[1].children[1]), it panics when values don't match route pattern.
+func URLNewsByType(typeParam string) string {
+	return "/news/" + goRouteURLParam(urlNewsByTypeType, "type", typeParam) + "/"
+}
+
+func goRouteURLParam(re *regexp.Regexp, name, value string) string {
+	if !re.MatchString(value) {
+		panic(fmt.Sprintf("value %q of param %q doesn't match %s", value, name, re))
+	}
+	return url.PathEscape(value)
+}
+`
+	src, err := generateURLs(entries, "web")
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if expected != string(src) {
+		t.Errorf("Expected generated code:\n%s\ngot:\n%s", expected, src)
+	}
+}
+
+func TestGenerateURLsErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		entries []entry
+
+		expectedError string
+	}{
+		{
+			name: "unsupported pattern",
+			entries: []entry{
+				{pattern: `^/(news|blog)$`, name: "list", source: "routes[0]"},
+			},
+			expectedError: `routes[0]: can't build URL of route "list": unsupported expression "news|blog" in pattern "^/(news|blog)$"`,
+		}, {
+			name: "no named routes",
+			entries: []entry{
+				{pattern: `^/$`, source: "routes[0]"},
+			},
+			expectedError: "no named routes found",
+		}, {
+			name: "duplicated group name",
+			entries: []entry{
+				{pattern: `^/(?P<pk>\d+)`, source: "routes[0]", children: []entry{
+					{pattern: `^/(?P<pk>\d+)$`, name: "detail", source: "routes[0].children[0]"},
+				}},
+			},
+			expectedError: `routes[0].children[0]: can't build URL of route "detail": group name "pk" is used more than once`,
+		}, {
+			name: "duplicated function name",
+			entries: []entry{
+				{pattern: `^/a$`, name: "news-list", source: "routes[0]"},
+				{pattern: `^/b$`, name: "news_list", source: "routes[1]"},
+			},
+			expectedError: `routes[1]: route "news_list" generates function URLNewsList already generated for routes[0]`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := generateURLs(tc.entries, "web")
+			if err == nil {
+				t.Fatalf("Expected error: '%s' but got nil.", tc.expectedError)
+			}
+			if err.Error() != tc.expectedError {
+				t.Errorf("Expected error '%s', got: '%s'", tc.expectedError, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+type issue struct {
+	source  string
+	message string
+	// warning is issue that may be false positive, it doesn't fail the lint.
+	warning bool
+}
+
+func (i issue) String() string {
+	if i.warning {
+		return i.source + ": warning: " + i.message
+	}
+	return i.source + ": " + i.message
+}
+
+// lint checks route table for patterns without ^ anchor, routes shadowed by earlier ones and group names used
+// more than once along a chain of routes. Route is reported as unreachable only when it's certain, routes that
+// only may be shadowed are reported as warnings.
+func lint(entries []entry) []issue {
+	return lintRoutes(entries, map[string]string{})
+}
+
+func lintRoutes(entries []entry, groups map[string]string) []issue {
+	var (
+		issues   []issue
+		compiled []*regexp.Regexp
+	)
+	for i, e := range entries {
+		re, err := regexp.Compile(e.pattern)
+		compiled = append(compiled, re)
+		if err != nil {
+			issues = append(issues, issue{source: e.source, message: fmt.Sprintf("invalid pattern %q: %v", e.pattern, err)})
+			continue
+		}
+		if !strings.HasPrefix(e.pattern, "^") {
+			issues = append(issues, issue{source: e.source, message: fmt.Sprintf("pattern %q is missing ^ anchor, it can match in the middle of path", e.pattern)})
+		}
+		if example, ok := examplePath(e.pattern); ok {
+			for j := 0; j < i; j++ {
+				if compiled[j] == nil || !compiled[j].MatchString(example) {
+					continue
+				}
+				if shadows(entries[j].pattern, e.pattern) {
+					issues = append(issues, issue{source: e.source, message: fmt.Sprintf("route %q is unreachable, path %q is matched by earlier route %q (%s)",
+						e.pattern, example, entries[j].pattern, entries[j].source)})
+				} else {
+					issues = append(issues, issue{source: e.source, warning: true, message: fmt.Sprintf("route %q may be unreachable, path %q is matched by earlier route %q (%s)",
+						e.pattern, example, entries[j].pattern, entries[j].source)})
+				}
+				break
+			}
+		}
+
+		chainGroups := map[string]string{}
+		for name, source := range groups {
+			chainGroups[name] = source
+		}
+		for _, name := range re.SubexpNames()[1:] {
+			if name == "" {
+				continue
+			}
+			if source, ok := chainGroups[name]; ok {
+				issues = append(issues, issue{source: e.source, message: fmt.Sprintf("group name %q is already used by %s", name, source)})
+				continue
+			}
+			chainGroups[name] = e.source
+		}
+		issues = append(issues, lintRoutes(e.children, chainGroups)...)
+	}
+	return issues
+}
+
+// shadows tells if earlier pattern matches every path matched by later one. It's known when patterns are the same,
+// later pattern matches single literal path already matched by earlier one, or earlier pattern is literal prefix
+// router covering literal prefix of later one.
+func shadows(earlier, later string) bool {
+	if earlier == later {
+		return true
+	}
+	l, ok := parseLiteral(later)
+	if !ok || !l.begin {
+		return false
+	}
+	if l.complete && l.end {
+		return true
+	}
+	e, ok := parseLiteral(earlier)
+	return ok && e.begin && e.complete && !e.end && strings.HasPrefix(l.prefix, e.prefix)
+}
+
+// literalPattern describes literal part of pattern, complete is set when pattern has nothing but the literal and
+// anchors.
+type literalPattern struct {
+	prefix     string
+	complete   bool
+	begin, end bool
+}
+
+func parseLiteral(pattern string) (literalPattern, bool) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return literalPattern{}, false
+	}
+	parsed, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return literalPattern{}, false
+	}
+	subs := []*syntax.Regexp{parsed.Simplify()}
+	if subs[0].Op == syntax.OpConcat {
+		subs = subs[0].Sub
+	}
+	l := literalPattern{
+		begin: subs[0].Op == syntax.OpBeginText,
+		end:   subs[len(subs)-1].Op == syntax.OpEndText,
+	}
+	l.prefix, l.complete = re.LiteralPrefix()
+	return l, true
+}
+
+// examplePath generates shortest path matched by pattern, it's used to check if route is shadowed.
+func examplePath(pattern string) (string, bool) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	var b strings.Builder
+	if !generate(&b, re.Simplify()) {
+		return "", false
+	}
+	return b.String(), true
+}
+
+func generate(b *strings.Builder, re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpLiteral:
+		b.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		if len(re.Rune) == 0 {
+			return false
+		}
+		b.WriteRune(classRune(re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteRune('x')
+	case syntax.OpCapture, syntax.OpPlus:
+		return generate(b, re.Sub[0])
+	case syntax.OpRepeat:
+		for i := 0; i < re.Min; i++ {
+			if !generate(b, re.Sub[0]) {
+				return false
+			}
+		}
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if !generate(b, sub) {
+				return false
+			}
+		}
+	case syntax.OpAlternate:
+		return generate(b, re.Sub[0])
+	case syntax.OpStar, syntax.OpQuest, syntax.OpEmptyMatch, syntax.OpBeginLine, syntax.OpEndLine,
+		syntax.OpBeginText, syntax.OpEndText:
+	default:
+		return false
+	}
+	return true
+}
+
+// classRune picks readable rune from character class ranges.
+func classRune(ranges []rune) rune {
+	for _, r := range "a0x-_" {
+		for i := 0; i+1 < len(ranges); i += 2 {
+			if ranges[i] <= r && r <= ranges[i+1] {
+				return r
+			}
+		}
+	}
+	return ranges[0]
+}
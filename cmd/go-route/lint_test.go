@@ -0,0 +1,42 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	entries := []entry{
+		{pattern: `^/$`, source: "routes[0]"},
+		{pattern: `^/news`, source: "routes[1]", children: []entry{
+			{pattern: `^/(?P<pk>\d+)$`, source: "routes[1].children[0]"},
+			{pattern: `^/(?P<pk>\d+)$`, source: "routes[1].children[1]", methods: []string{"POST"}},
+			{pattern: `^/(?P<id>\d+),(?P<id>[a-z]+)$`, source: "routes[1].children[2]"},
+		}},
+		{pattern: `^/news-archive$`, source: "routes[2]"},
+		{pattern: `/blog/(?P<pk>\d+)$`, source: "routes[3]"},
+		{pattern: `^/(?P<pk>[a-z`, source: "routes[4]"},
+		{pattern: `^/(?P<pk>\d+)`, source: "routes[5]", children: []entry{
+			{pattern: `^/(?P<pk>\d+)$`, source: "routes[5].children[0]"},
+		}},
+		{pattern: `^/page/0$`, source: "routes[6]"},
+		{pattern: `^/page/(?P<n>\d+)$`, source: "routes[7]"},
+	}
+
+	expected := []string{
+		`routes[1].children[1]: route "^/(?P<pk>\\d+)$" is unreachable, path "/0" is matched by earlier route "^/(?P<pk>\\d+)$" (routes[1].children[0])`,
+		`routes[1].children[2]: group name "id" is already used by routes[1].children[2]`,
+		`routes[2]: route "^/news-archive$" is unreachable, path "/news-archive" is matched by earlier route "^/news" (routes[1])`,
+		`routes[3]: pattern "/blog/(?P<pk>\\d+)$" is missing ^ anchor, it can match in the middle of path`,
+		"routes[4]: invalid pattern \"^/(?P<pk>[a-z\": error parsing regexp: missing closing ]: `[a-z`",
+		`routes[5].children[0]: group name "pk" is already used by routes[5]`,
+		`routes[7]: warning: route "^/page/(?P<n>\\d+)$" may be unreachable, path "/page/0" is matched by earlier route "^/page/0$" (routes[6])`,
+	}
+	var got []string
+	for _, i := range lint(entries) {
+		got = append(got, i.String())
+	}
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("Expected issues:\n%v\ngot:\n%v", expected, got)
+	}
+}
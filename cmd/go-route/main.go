@@ -0,0 +1,133 @@
+// Command go-route lints route tables and explains which route matches given request.
+//
+// Usage:
+//
+//	go-route lint (-config routes.json | -pkg ./dir)
+//	go-route match (-config routes.json | -pkg ./dir) METHOD PATH
+//	go-route gen (-config routes.json | -pkg ./dir) [-o urls.go] [-package name]
+//
+// Route table is read either from JSON file in format accepted by route.Registry, or from Go package, by
+// listing calls to Add, AddWithMeta and Route with literal patterns. Handlers registered on MethodRoute are
+// found only when chained directly to Route call, i.e. r.Route("^/items$").Get(list).Post(create).
+//
+// The gen command generates type-safe URL builder functions for named routes, i.e. URLNewsDetail(pk int,
+// slug string) string for route named "news-detail". It's meant to be used with go generate:
+//
+//	//go:generate go run github.com/Alkemic/go-route/cmd/go-route gen -config routes.json -o urls.go
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+)
+
+const usage = `usage:
+	go-route lint (-config routes.json | -pkg ./dir)
+	go-route match (-config routes.json | -pkg ./dir) METHOD PATH
+	go-route gen (-config routes.json | -pkg ./dir) [-o urls.go] [-package name]
+`
+
+var errIssues = errors.New("issues found")
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		if err != errIssues {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+	flags := flag.NewFlagSet("go-route "+args[0], flag.ContinueOnError)
+	config := flags.String("config", "", "JSON route table")
+	pkg := flags.String("pkg", "", "directory of Go package registering routes")
+	output := flags.String("o", "", "output file of generated code, standard output by default")
+	pkgName := flags.String("package", os.Getenv("GOPACKAGE"), "package name of generated code")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	var (
+		entries []entry
+		err     error
+	)
+	switch {
+	case *config != "" && *pkg == "":
+		entries, err = loadConfig(*config)
+	case *pkg != "" && *config == "":
+		entries, err = loadPackage(*pkg)
+	default:
+		return errors.New("exactly one of -config or -pkg must be given\n" + usage)
+	}
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "lint":
+		failed := false
+		for _, i := range lint(entries) {
+			fmt.Fprintln(out, i)
+			failed = failed || !i.warning
+		}
+		if failed {
+			return errIssues
+		}
+		return nil
+	case "match":
+		if flags.NArg() != 2 {
+			return errors.New(usage)
+		}
+		return explain(entries, flags.Arg(0), flags.Arg(1), out)
+	case "gen":
+		if *pkgName == "" {
+			return errors.New("package name must be given with -package")
+		}
+		src, err := generateURLs(entries, *pkgName)
+		if err != nil {
+			return err
+		}
+		if *output == "" {
+			_, err = out.Write(src)
+			return err
+		}
+		return ioutil.WriteFile(*output, src, 0644)
+	}
+	return fmt.Errorf("unknown command %q\n%s", args[0], usage)
+}
+
+func explain(entries []entry, method, path string, out io.Writer) error {
+	router, err := buildRouter(entries)
+	if err != nil {
+		return err
+	}
+	m := router.Match(httptest.NewRequest(method, path, nil))
+	fmt.Fprintf(out, "%s %s: %d %s\n", method, path, m.Status, http.StatusText(m.Status))
+	for i, r := range m.Routes {
+		fmt.Fprintf(out, "%*s%s", i*2, "", r.Pattern)
+		if r.Meta.Name != "" {
+			fmt.Fprintf(out, " (%s)", r.Meta.Name)
+		}
+		fmt.Fprintln(out)
+	}
+	var keys []string
+	for key := range m.Params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(out, "%s = %q\n", key, m.Params[key])
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-route")
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	defer os.RemoveAll(dir)
+	config := filepath.Join(dir, "routes.json")
+	ioutil.WriteFile(config, []byte(`{"routes": [
+		{"pattern": "^/news", "children": [
+			{"pattern": "^/(?P<pk>\\d+),(?P<slug>[a-z]+)\\.html$", "name": "news-detail", "handler": "news", "methods": ["GET"]}
+		]},
+		{"pattern": "/blog$", "handler": "blog"}
+	]}`), 0644)
+	source := filepath.Join(dir, "routes.go")
+	ioutil.WriteFile(source, []byte(`package web
+
+func routes() {
+	newsRoutes := route.New().
+		Add("^/(?P<pk>\\d+)$", news, http.MethodGet)
+	newsRoutes.AddWithMeta("^/$", newsList, route.Meta{Name: "list"}, "GET", "POST")
+
+	routing := route.New()
+	routing.Add("^/news", newsRoutes)
+	routing.Route("^/items$").Get(items).Post(createItem).Meta(route.Meta{Name: "items"})
+	routing.Route("^/items/0$").Get(firstItem)
+	routing.Add("^/items/(?P<pk>\\d+)$", item)
+	routing.Add("^/", index)
+}
+`), 0644)
+
+	testCases := []struct {
+		name string
+		args []string
+
+		expectedError  bool
+		expectedOutput string
+	}{
+		{
+			name:           "match from config",
+			args:           []string{"match", "-config", config, "GET", "/news/12,abc.html"},
+			expectedOutput: "GET /news/12,abc.html: 200 OK\n^/news\n  ^/(?P<pk>\\d+),(?P<slug>[a-z]+)\\.html$ (news-detail)\npk = \"12\"\nslug = \"abc\"\n",
+		}, {
+			name:           "lint config",
+			args:           []string{"lint", "-config", config},
+			expectedError:  true,
+			expectedOutput: "routes[1]: pattern \"/blog$\" is missing ^ anchor, it can match in the middle of path\n",
+		}, {
+			name:           "match from package",
+			args:           []string{"match", "-pkg", dir, "POST", "/news/12"},
+			expectedOutput: "POST /news/12: 405 Method Not Allowed\n^/news\n",
+		}, {
+			name:           "match method route from package",
+			args:           []string{"match", "-pkg", dir, "DELETE", "/items"},
+			expectedOutput: "DELETE /items: 405 Method Not Allowed\n",
+		}, {
+			name:           "match method route handler from package",
+			args:           []string{"match", "-pkg", dir, "POST", "/items"},
+			expectedOutput: "POST /items: 200 OK\n^/items$ (items)\n",
+		}, {
+			name:           "match head of method route from package",
+			args:           []string{"match", "-pkg", dir, "HEAD", "/items"},
+			expectedOutput: "HEAD /items: 200 OK\n^/items$ (items)\n",
+		}, {
+			name:           "lint package",
+			args:           []string{"lint", "-pkg", dir},
+			expectedOutput: "routes.go:12: warning: route \"^/items/(?P<pk>\\\\d+)$\" may be unreachable, path \"/items/0\" is matched by earlier route \"^/items/0$\" (routes.go:11)\n",
+		}, {
+			name: "gen from package",
+			args: []string{"gen", "-pkg", dir, "-package", "web"},
+			expectedOutput: "// Code generated by go-route gen; DO NOT EDIT.\n\npackage web\n\n" +
+				"// URLList returns path of route \"list\" (routes.go:6).\nfunc URLList() string {\n\treturn \"/news/\"\n}\n\n" +
+				"// URLItems returns path of route \"items\" (routes.go:10).\nfunc URLItems() string {\n\treturn \"/items\"\n}\n",
+		}, {
+			name:          "missing source",
+			args:          []string{"lint"},
+			expectedError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := run(tc.args, &out)
+			if tc.expectedError != (err != nil) {
+				t.Errorf("Unexpected error: '%v'", err)
+			}
+			if tc.expectedOutput != out.String() {
+				t.Errorf("Expected output '%s', got '%s'", tc.expectedOutput, out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,353 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Alkemic/go-route"
+)
+
+// entry is a single route from route table, source points to its definition.
+type entry struct {
+	pattern  string
+	name     string
+	methods  []string
+	children []entry
+	source   string
+}
+
+func loadConfig(path string) ([]entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	cfg, err := route.ParseConfig(f)
+	if err != nil {
+		return nil, err
+	}
+	return configEntries(cfg.Routes, "routes"), nil
+}
+
+func configEntries(routes []route.RouteConfig, path string) []entry {
+	var entries []entry
+	for i, rc := range routes {
+		source := fmt.Sprintf("%s[%d]", path, i)
+		entries = append(entries, entry{
+			pattern:  rc.Pattern,
+			name:     rc.Name,
+			methods:  rc.Methods,
+			children: configEntries(rc.Children, source+".children"),
+			source:   source,
+		})
+	}
+	return entries
+}
+
+var httpMethods = map[string]string{
+	"MethodGet":     http.MethodGet,
+	"MethodHead":    http.MethodHead,
+	"MethodPost":    http.MethodPost,
+	"MethodPut":     http.MethodPut,
+	"MethodPatch":   http.MethodPatch,
+	"MethodDelete":  http.MethodDelete,
+	"MethodConnect": http.MethodConnect,
+	"MethodOptions": http.MethodOptions,
+	"MethodTrace":   http.MethodTrace,
+}
+
+type sourceRoute struct {
+	entry
+	handler string
+}
+
+// loadPackage lists routes registered in Go package by calls to Add, AddWithMeta and Route with literal patterns.
+// Routers are identified by variable names, router passed as a handler to another router becomes its child.
+// Methods of Route are read only when chained directly to it, i.e. r.Route("^/$").Get(list).Post(create).
+func loadPackage(dir string) ([]entry, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	routers := map[string][]sourceRoute{}
+	var order []string
+	record := func(name string, calls []routeCall) {
+		if name == "" || len(calls) == 0 {
+			return
+		}
+		if _, ok := routers[name]; !ok {
+			order = append(order, name)
+		}
+		for i := len(calls) - 1; i >= 0; i-- {
+			if r, ok := sourceRouteFromCall(fset, calls[i]); ok {
+				routers[name] = append(routers[name], r)
+			}
+		}
+	}
+
+	var names []string
+	for name := range pkgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var files []string
+		for file := range pkgs[name].Files {
+			files = append(files, file)
+		}
+		sort.Strings(files)
+		for _, file := range files {
+			ast.Inspect(pkgs[name].Files[file], func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.AssignStmt:
+					if len(n.Lhs) == 1 && len(n.Rhs) == 1 {
+						if ident, ok := n.Lhs[0].(*ast.Ident); ok {
+							calls, root := addCalls(n.Rhs[0])
+							if root == "" {
+								root = ident.Name
+							}
+							record(root, calls)
+							return false
+						}
+					}
+				case *ast.ExprStmt:
+					calls, root := addCalls(n.X)
+					record(root, calls)
+					return false
+				}
+				return true
+			})
+		}
+	}
+
+	children := map[string]bool{}
+	for _, name := range order {
+		for _, r := range routers[name] {
+			if _, ok := routers[r.handler]; ok {
+				children[r.handler] = true
+			}
+		}
+	}
+	var visit func(name string, seen map[string]bool) []entry
+	visit = func(name string, seen map[string]bool) []entry {
+		var entries []entry
+		seen[name] = true
+		for _, r := range routers[name] {
+			e := r.entry
+			if _, ok := routers[r.handler]; ok && !seen[r.handler] {
+				e.children = visit(r.handler, seen)
+			}
+			entries = append(entries, e)
+		}
+		delete(seen, name)
+		return entries
+	}
+	var entries []entry
+	for _, name := range order {
+		if !children[name] {
+			entries = append(entries, visit(name, map[string]bool{})...)
+		}
+	}
+	return entries, nil
+}
+
+// chainMethods are methods of RegexpRouter returning the router itself.
+var chainMethods = map[string]bool{
+	"Add":           true,
+	"AddWithMeta":   true,
+	"AddMiddleware": true,
+	"EnableCache":   true,
+}
+
+// methodRouteMethods are methods of MethodRoute registering handler for a method.
+var methodRouteMethods = map[string]string{
+	"Get":     http.MethodGet,
+	"Head":    http.MethodHead,
+	"Post":    http.MethodPost,
+	"Put":     http.MethodPut,
+	"Patch":   http.MethodPatch,
+	"Delete":  http.MethodDelete,
+	"Options": http.MethodOptions,
+}
+
+// routeCall is call adding route, chained are calls on MethodRoute returned by Route, i.e. Get or Meta.
+type routeCall struct {
+	call    *ast.CallExpr
+	chained []*ast.CallExpr
+}
+
+// addCalls returns calls to Add, AddWithMeta and Route in call chain, from the last one, along with name of
+// variable the chain starts with. Name is empty when chain starts with other call, i.e. route.New().
+func addCalls(expr ast.Expr) ([]routeCall, string) {
+	var (
+		calls   []routeCall
+		chained []*ast.CallExpr
+	)
+	for {
+		switch e := expr.(type) {
+		case *ast.Ident:
+			return calls, e.Name
+		case *ast.CallExpr:
+			sel, ok := e.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return calls, ""
+			}
+			name := sel.Sel.Name
+			switch _, method := methodRouteMethods[name]; {
+			case len(calls) == 0 && (method || name == "Method" || name == "Meta"):
+				chained = append([]*ast.CallExpr{e}, chained...)
+			case name == "Route":
+				calls = append(calls, routeCall{call: e, chained: chained})
+				chained = nil
+			case chainMethods[name]:
+				if name == "Add" || name == "AddWithMeta" {
+					calls = append(calls, routeCall{call: e})
+				}
+			default:
+				return calls, ""
+			}
+			expr = sel.X
+		default:
+			return calls, ""
+		}
+	}
+}
+
+func sourceRouteFromCall(fset *token.FileSet, rc routeCall) (sourceRoute, bool) {
+	call := rc.call
+	if len(call.Args) < 1 {
+		return sourceRoute{}, false
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return sourceRoute{}, false
+	}
+	pattern, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return sourceRoute{}, false
+	}
+	pos := fset.Position(call.Pos())
+	r := sourceRoute{entry: entry{
+		pattern: pattern,
+		source:  fmt.Sprintf("%s:%d", filepath.Base(pos.Filename), pos.Line),
+	}}
+	if call.Fun.(*ast.SelectorExpr).Sel.Name == "Route" {
+		for _, c := range rc.chained {
+			switch name := c.Fun.(*ast.SelectorExpr).Sel.Name; name {
+			case "Method":
+				if len(c.Args) > 0 {
+					r.methods = append(r.methods, methodArgs(c.Args[:1])...)
+				}
+			case "Meta":
+				if len(c.Args) > 0 {
+					r.name = metaName(c.Args[0])
+				}
+			default:
+				r.methods = append(r.methods, methodRouteMethods[name])
+			}
+		}
+		// HEAD is served by GET handler
+		for _, method := range r.methods {
+			if method == http.MethodGet {
+				r.methods = append(r.methods, http.MethodHead)
+				break
+			}
+		}
+		return r, true
+	}
+	if len(call.Args) < 2 {
+		return sourceRoute{}, false
+	}
+	if ident, ok := call.Args[1].(*ast.Ident); ok {
+		r.handler = ident.Name
+	}
+	args := call.Args[2:]
+	if call.Fun.(*ast.SelectorExpr).Sel.Name == "AddWithMeta" && len(args) > 0 {
+		r.name = metaName(args[0])
+		args = args[1:]
+	}
+	r.methods = methodArgs(args)
+	return r, true
+}
+
+// metaName returns Name of route.Meta literal, i.e. route.Meta{Name: "news-detail"}.
+func metaName(expr ast.Expr) string {
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return ""
+	}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok || key.Name != "Name" {
+			continue
+		}
+		if value, ok := kv.Value.(*ast.BasicLit); ok && value.Kind == token.STRING {
+			name, _ := strconv.Unquote(value.Value)
+			return name
+		}
+	}
+	return ""
+}
+
+// methodArgs returns methods given as http.Method* constants or string literals.
+func methodArgs(args []ast.Expr) []string {
+	var methods []string
+	for _, arg := range args {
+		switch a := arg.(type) {
+		case *ast.SelectorExpr:
+			if method, ok := httpMethods[a.Sel.Name]; ok {
+				methods = append(methods, method)
+			}
+		case *ast.BasicLit:
+			if method, err := strconv.Unquote(a.Value); err == nil {
+				methods = append(methods, method)
+			}
+		}
+	}
+	return methods
+}
+
+// buildRouter creates router from route table with handlers doing nothing, so requests can be matched against it.
+func buildRouter(entries []entry) (*route.RegexpRouter, error) {
+	router := route.New()
+	for _, e := range entries {
+		var handler interface{} = func(http.ResponseWriter, *http.Request) {}
+		if len(e.children) > 0 {
+			sub, err := buildRouter(e.children)
+			if err != nil {
+				return nil, err
+			}
+			handler = sub
+		}
+		if err := addRoute(router, e, handler); err != nil {
+			return nil, err
+		}
+	}
+	return router, nil
+}
+
+func addRoute(router *route.RegexpRouter, e entry, handler interface{}) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%s: %v", e.source, r)
+		}
+	}()
+	router.AddWithMeta(e.pattern, handler, route.Meta{Name: e.name}, e.methods...)
+	return nil
+}
@@ -0,0 +1,235 @@
+package route
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrUnknownHandler    = errors.New("unknown handler")
+	ErrUnknownMiddleware = errors.New("unknown middleware")
+	ErrInvalidRoute      = errors.New("invalid route")
+)
+
+// Config is route table, usually loaded from JSON. Middlewares run in listed order, the first one is the outermost.
+type Config struct {
+	Middleware []string      `json:"middleware,omitempty"`
+	Routes     []RouteConfig `json:"routes"`
+}
+
+// RouteConfig describes single route. Exactly one of Handler, Redirect or Children must be set, Middleware
+// wraps the handler or, for routes with children, is added to the sub router, in listed order like Chain.
+// RedirectStatus must be 3xx, it defaults to 302.
+type RouteConfig struct {
+	Pattern        string        `json:"pattern"`
+	Name           string        `json:"name,omitempty"`
+	Handler        string        `json:"handler,omitempty"`
+	Redirect       string        `json:"redirect,omitempty"`
+	RedirectStatus int           `json:"redirect_status,omitempty"`
+	Methods        []string      `json:"methods,omitempty"`
+	Middleware     []string      `json:"middleware,omitempty"`
+	Children       []RouteConfig `json:"children,omitempty"`
+}
+
+// ConfigError describes problem with route table, Path points to the route, i.e. "routes[1].children[0]".
+type ConfigError struct {
+	Path    string
+	Pattern string
+	Err     error
+}
+
+func (e *ConfigError) Error() string {
+	if e.Pattern == "" {
+		return fmt.Sprintf("%s: %v", e.Path, e.Err)
+	}
+	return fmt.Sprintf("%s (%q): %v", e.Path, e.Pattern, e.Err)
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// ParseConfig reads JSON route table, unknown fields are rejected.
+func ParseConfig(r io.Reader) (*Config, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("can't read route table: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	cfg := &Config{}
+	if err := decoder.Decode(cfg); err != nil {
+		var (
+			syntaxErr *json.SyntaxError
+			typeErr   *json.UnmarshalTypeError
+		)
+		switch {
+		case errors.As(err, &syntaxErr):
+			return nil, fmt.Errorf("can't parse route table at %s: %w", position(data, syntaxErr.Offset), err)
+		case errors.As(err, &typeErr):
+			return nil, fmt.Errorf("can't parse route table at %s: %w", position(data, typeErr.Offset), err)
+		}
+		return nil, fmt.Errorf("can't parse route table: %w", err)
+	}
+	return cfg, nil
+}
+
+func position(data []byte, offset int64) string {
+	// offset points just after the byte that caused the error
+	i := int(offset) - 1
+	if i < 0 {
+		i = 0
+	} else if i > len(data) {
+		i = len(data)
+	}
+	line := bytes.Count(data[:i], []byte("\n")) + 1
+	column := i - bytes.LastIndexByte(data[:i], '\n')
+	return fmt.Sprintf("line %d, column %d", line, column)
+}
+
+// Registry maps names used in route table into handlers and middlewares registered in code.
+type Registry struct {
+	handlers    map[string]http.HandlerFunc
+	middlewares map[string]Middleware
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		handlers:    map[string]http.HandlerFunc{},
+		middlewares: map[string]Middleware{},
+	}
+}
+
+// Handler registers handler under given name.
+func (reg *Registry) Handler(name string, handler http.HandlerFunc) *Registry {
+	reg.handlers[name] = handler
+	return reg
+}
+
+// Middleware registers middleware under given name.
+func (reg *Registry) Middleware(name string, mw Middleware) *Registry {
+	reg.middlewares[name] = mw
+	return reg
+}
+
+// Load reads JSON route table and builds tree of routers from it.
+func (reg *Registry) Load(r io.Reader) (*RegexpRouter, error) {
+	cfg, err := ParseConfig(r)
+	if err != nil {
+		return nil, err
+	}
+	return reg.Build(cfg)
+}
+
+// Build creates tree of routers from route table, resolving handler and middleware names against registry.
+func (reg *Registry) Build(cfg *Config) (*RegexpRouter, error) {
+	router := New()
+	var middlewares []Middleware
+	for _, name := range cfg.Middleware {
+		mw, ok := reg.middlewares[name]
+		if !ok {
+			return nil, &ConfigError{Path: "middleware", Err: fmt.Errorf("%w %q", ErrUnknownMiddleware, name)}
+		}
+		middlewares = append(middlewares, mw)
+	}
+	if len(middlewares) > 0 {
+		router.AddMiddleware(NewChain(middlewares...).Middleware())
+	}
+	if err := reg.addRoutes(router, "routes", cfg.Routes); err != nil {
+		return nil, err
+	}
+	return router, nil
+}
+
+func (reg *Registry) addRoutes(router *RegexpRouter, path string, routes []RouteConfig) error {
+	for i, rc := range routes {
+		if err := reg.addRoute(router, fmt.Sprintf("%s[%d]", path, i), rc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (reg *Registry) addRoute(router *RegexpRouter, path string, rc RouteConfig) error {
+	fail := func(err error) error {
+		return &ConfigError{Path: path, Pattern: rc.Pattern, Err: err}
+	}
+
+	if _, err := regexp.Compile(rc.Pattern); err != nil {
+		return fail(err)
+	}
+	for _, method := range rc.Methods {
+		if _, ok := defaultMethods[method]; !ok {
+			return fail(fmt.Errorf("%w: unknown method %q", ErrInvalidRoute, method))
+		}
+	}
+	targets := 0
+	for _, set := range []bool{rc.Handler != "", rc.Redirect != "", len(rc.Children) > 0} {
+		if set {
+			targets++
+		}
+	}
+	if targets != 1 {
+		return fail(fmt.Errorf("%w: exactly one of handler, redirect or children must be set", ErrInvalidRoute))
+	}
+	if rc.RedirectStatus != 0 && (rc.RedirectStatus < 300 || rc.RedirectStatus > 399) {
+		return fail(fmt.Errorf("%w: redirect status %d is not 3xx", ErrInvalidRoute, rc.RedirectStatus))
+	}
+	var middlewares []Middleware
+	for _, name := range rc.Middleware {
+		mw, ok := reg.middlewares[name]
+		if !ok {
+			return fail(fmt.Errorf("%w %q", ErrUnknownMiddleware, name))
+		}
+		middlewares = append(middlewares, mw)
+	}
+
+	meta := Meta{Name: rc.Name}
+	if len(rc.Children) > 0 {
+		sub := New()
+		if len(middlewares) > 0 {
+			sub.AddMiddleware(NewChain(middlewares...).Middleware())
+		}
+		if err := reg.addRoutes(sub, path+".children", rc.Children); err != nil {
+			return err
+		}
+		router.AddWithMeta(rc.Pattern, sub, meta, rc.Methods...)
+		return nil
+	}
+
+	var handler http.HandlerFunc
+	if rc.Redirect != "" {
+		handler = redirectHandler(rc.Redirect, rc.RedirectStatus)
+	} else {
+		var ok bool
+		if handler, ok = reg.handlers[rc.Handler]; !ok {
+			return fail(fmt.Errorf("%w %q", ErrUnknownHandler, rc.Handler))
+		}
+	}
+	handler = NewChain(middlewares...).ThenFunc(handler)
+	router.AddWithMeta(rc.Pattern, handler, meta, rc.Methods...)
+	return nil
+}
+
+// redirectHandler redirects to target, "{name}" placeholders in target are replaced with path escaped url params,
+// so params can't change host or path structure of the target.
+func redirectHandler(target string, status int) http.HandlerFunc {
+	if status == 0 {
+		status = http.StatusFound
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		var replacements []string
+		for key, value := range GetParams(r) {
+			replacements = append(replacements, "{"+key+"}", url.PathEscape(value))
+		}
+		http.Redirect(w, r, strings.NewReplacer(replacements...).Replace(target), status)
+	}
+}
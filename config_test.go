@@ -0,0 +1,203 @@
+package route
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistryLoad(t *testing.T) {
+	registry := NewRegistry().
+		Handler("news", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, "news %s", GetParams(r)["pk"])
+		}).
+		Middleware("header", func(fn http.HandlerFunc) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Test", "1")
+				fn(w, r)
+			}
+		})
+
+	router, err := registry.Load(strings.NewReader(`{
+		"routes": [
+			{"pattern": "^/news", "middleware": ["header"], "children": [
+				{"pattern": "^/(?P<pk>\\d+)$", "name": "news-detail", "handler": "news", "methods": ["GET"]}
+			]},
+			{"pattern": "^/n/(?P<pk>\\d+)$", "handler": "news"},
+			{"pattern": "^/old/(?P<pk>\\d+)$", "redirect": "/news/{pk}", "redirect_status": 301},
+			{"pattern": "^/go/(?P<to>.*)$", "redirect": "/{to}"}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+
+		expectedStatusCode int
+		expectedBody       string
+		expectedHeaders    map[string]string
+	}{
+		{
+			name:               "child route with middleware",
+			method:             http.MethodGet,
+			path:               "/news/12",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "news 12",
+			expectedHeaders:    map[string]string{"X-Test": "1"},
+		}, {
+			name:               "method restriction",
+			method:             http.MethodPost,
+			path:               "/news/12",
+			expectedStatusCode: http.StatusMethodNotAllowed,
+			expectedBody:       "Method Not Allowed\n",
+		}, {
+			name:               "alias",
+			method:             http.MethodPost,
+			path:               "/n/7",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "news 7",
+			expectedHeaders:    map[string]string{"X-Test": ""},
+		}, {
+			name:               "redirect",
+			method:             http.MethodGet,
+			path:               "/old/7",
+			expectedStatusCode: http.StatusMovedPermanently,
+			expectedHeaders:    map[string]string{"Location": "/news/7"},
+		}, {
+			name:               "redirect param can't change host",
+			method:             http.MethodGet,
+			path:               "/go//evil.com",
+			expectedStatusCode: http.StatusFound,
+			expectedHeaders:    map[string]string{"Location": "/%2Fevil.com"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "http://example.com"+tc.path, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if tc.expectedStatusCode != w.Code {
+				t.Errorf("Expected status code '%d', but got '%d'", tc.expectedStatusCode, w.Code)
+			}
+			if tc.expectedBody != "" && tc.expectedBody != w.Body.String() {
+				t.Errorf("Expected body '%s', but got '%s'", tc.expectedBody, w.Body.String())
+			}
+			for k, v := range tc.expectedHeaders {
+				if h := w.Header().Get(k); h != v {
+					t.Errorf("Expected header '%s' to be '%s', but got '%s'", k, v, h)
+				}
+			}
+		})
+	}
+}
+
+func TestRegistryLoadErrors(t *testing.T) {
+	registry := NewRegistry().Handler("news", func(w http.ResponseWriter, r *http.Request) {})
+
+	testCases := []struct {
+		name   string
+		config string
+
+		expectedError string
+		expectedIs    error
+	}{
+		{
+			name:          "syntax error",
+			config:        "{\n\"routes\": [\n{\"pattern\" \"^/\"}]}",
+			expectedError: "can't parse route table at line 3, column 12: invalid character '\"' after object key",
+		}, {
+			name:          "unknown field",
+			config:        `{"routes": [{"pattern": "^/", "handlr": "news"}]}`,
+			expectedError: "can't parse route table: json: unknown field \"handlr\"",
+		}, {
+			name:          "unknown handler",
+			config:        `{"routes": [{"pattern": "^/news", "children": [{"pattern": "^/$", "handler": "list"}]}]}`,
+			expectedError: `routes[0].children[0] ("^/$"): unknown handler "list"`,
+			expectedIs:    ErrUnknownHandler,
+		}, {
+			name:          "unknown middleware",
+			config:        `{"middleware": ["auth"], "routes": []}`,
+			expectedError: `middleware: unknown middleware "auth"`,
+			expectedIs:    ErrUnknownMiddleware,
+		}, {
+			name:          "bad regexp",
+			config:        `{"routes": [{"pattern": "^/(?P<pk>\\d+$", "handler": "news"}]}`,
+			expectedError: "routes[0] (\"^/(?P<pk>\\\\d+$\"): error parsing regexp: missing closing ): `^/(?P<pk>\\d+$`",
+		}, {
+			name:          "unknown method",
+			config:        `{"routes": [{"pattern": "^/$", "handler": "news", "methods": ["get"]}]}`,
+			expectedError: `routes[0] ("^/$"): invalid route: unknown method "get"`,
+			expectedIs:    ErrInvalidRoute,
+		}, {
+			name:          "handler and redirect",
+			config:        `{"routes": [{"pattern": "^/$", "handler": "news", "redirect": "/"}]}`,
+			expectedError: `routes[0] ("^/$"): invalid route: exactly one of handler, redirect or children must be set`,
+			expectedIs:    ErrInvalidRoute,
+		}, {
+			name:          "redirect status not 3xx",
+			config:        `{"routes": [{"pattern": "^/$", "redirect": "/news", "redirect_status": 200}]}`,
+			expectedError: `routes[0] ("^/$"): invalid route: redirect status 200 is not 3xx`,
+			expectedIs:    ErrInvalidRoute,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := registry.Load(strings.NewReader(tc.config))
+			if err == nil {
+				t.Fatalf("Expected error: '%s' but got nil.", tc.expectedError)
+			}
+			if err.Error() != tc.expectedError {
+				t.Errorf("Expected error '%s', got: '%s'", tc.expectedError, err.Error())
+			}
+			if tc.expectedIs != nil && !errors.Is(err, tc.expectedIs) {
+				t.Errorf("Expected error to be '%v'", tc.expectedIs)
+			}
+		})
+	}
+}
+
+func TestRegistryMiddlewareOrder(t *testing.T) {
+	mark := func(name string) Middleware {
+		return func(fn http.HandlerFunc) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(name))
+				fn(w, r)
+			}
+		}
+	}
+	registry := NewRegistry().
+		Handler("news", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("news")) }).
+		Middleware("a", mark("a ")).
+		Middleware("b", mark("b ")).
+		Middleware("c", mark("c ")).
+		Middleware("d", mark("d "))
+	router, err := registry.Load(strings.NewReader(`{
+		"middleware": ["a", "b"],
+		"routes": [
+			{"pattern": "^/news", "middleware": ["c", "d"], "children": [
+				{"pattern": "^/$", "handler": "news", "middleware": ["d", "c"]}
+			]}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/news/", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	expectedBody := "a b c d d c news"
+	if w.Body.String() != expectedBody {
+		t.Errorf("Expected body '%s', but got '%s'", expectedBody, w.Body.String())
+	}
+}
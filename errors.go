@@ -0,0 +1,116 @@
+package route
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var errorsKey = "_errors_"
+
+// ErrorHandlerFunc is handler returning error, returned errors are turned into responses by router's error
+// handling, see RegexpRouter.ErrorHandler and RegexpRouter.MapError.
+type ErrorHandlerFunc func(http.ResponseWriter, *http.Request) error
+
+func (f ErrorHandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.handle(w, r)
+}
+
+func (f ErrorHandlerFunc) handle(w http.ResponseWriter, r *http.Request) {
+	if err := f(w, r); err != nil {
+		HandleError(w, r, err)
+	}
+}
+
+// HTTPError is error carrying HTTP status code, and optionally message returned to the client.
+type HTTPError struct {
+	Code    int
+	Message string
+	Err     error
+}
+
+// NewHTTPError returns HTTPError with given status code and message, empty message means status text.
+func NewHTTPError(code int, message string) *HTTPError {
+	return &HTTPError{Code: code, Message: message}
+}
+
+func (e *HTTPError) Error() string {
+	message := e.Message
+	if message == "" {
+		message = http.StatusText(e.Code)
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("%d %s: %v", e.Code, message, e.Err)
+	}
+	return fmt.Sprintf("%d %s", e.Code, message)
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+type errorMapping struct {
+	target error
+	code   int
+}
+
+// errorConfig is error handling of routers handling the request, from the innermost to the outermost one.
+type errorConfig struct {
+	handler  func(w http.ResponseWriter, r *http.Request, err error)
+	mappings []errorMapping
+	parent   *errorConfig
+}
+
+// MapError makes errors matching target (using errors.Is) returned by ErrorHandlerFunc handlers to be answered
+// with given status code. Mappings are inherited by sub routers.
+func (r *RegexpRouter) MapError(target error, code int) *RegexpRouter {
+	r.errorMappings = append(r.errorMappings, errorMapping{target: target, code: code})
+	return r
+}
+
+func (r RegexpRouter) withErrorConfig(req *http.Request) *http.Request {
+	if r.ErrorHandler == nil && len(r.errorMappings) == 0 {
+		return req
+	}
+	parent, _ := req.Context().Value(errorsKey).(*errorConfig)
+	config := &errorConfig{handler: r.ErrorHandler, mappings: r.errorMappings, parent: parent}
+	return req.WithContext(context.WithValue(req.Context(), errorsKey, config))
+}
+
+// ErrorStatus returns status code for error: code of HTTPError, code mapped by MapError of routers handling the
+// request, or 500.
+func ErrorStatus(r *http.Request, err error) int {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code
+	}
+	config, _ := r.Context().Value(errorsKey).(*errorConfig)
+	for ; config != nil; config = config.parent {
+		for _, mapping := range config.mappings {
+			if errors.Is(err, mapping.target) {
+				return mapping.code
+			}
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+// HandleError answers request with error, using ErrorHandler of the innermost router handling the request that
+// has one, or plain response with status code from ErrorStatus.
+func HandleError(w http.ResponseWriter, r *http.Request, err error) {
+	config, _ := r.Context().Value(errorsKey).(*errorConfig)
+	for ; config != nil; config = config.parent {
+		if config.handler != nil {
+			config.handler(w, r, err)
+			return
+		}
+	}
+	status := ErrorStatus(r, err)
+	message := http.StatusText(status)
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && httpErr.Message != "" {
+		message = httpErr.Message
+	}
+	RenderError(w, r, status, message)
+}
@@ -0,0 +1,103 @@
+package route
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestErrorHandlerFunc(t *testing.T) {
+	var (
+		errNotFound  = errors.New("news not found")
+		errForbidden = errors.New("forbidden")
+	)
+	returning := func(err error) func(w http.ResponseWriter, r *http.Request) error {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			if err == nil {
+				fmt.Fprint(w, "ok")
+			}
+			return err
+		}
+	}
+
+	newsRoutes := New().
+		Add(`^/ok$`, returning(nil)).
+		Add(`^/missing$`, returning(fmt.Errorf("loading news: %w", errNotFound))).
+		Add(`^/forbidden$`, ErrorHandlerFunc(returning(errForbidden))).
+		Add(`^/teapot$`, returning(NewHTTPError(http.StatusTeapot, "short and stout"))).
+		Add(`^/conflict$`, returning(&HTTPError{Code: http.StatusConflict, Err: errNotFound})).
+		Add(`^/failure$`, returning(errors.New("db down"))).
+		MapError(errNotFound, http.StatusNotFound)
+	adminRoutes := New().
+		Add(`^/failure$`, returning(errors.New("db down")))
+	adminRoutes.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		w.WriteHeader(ErrorStatus(r, err))
+		fmt.Fprintf(w, "admin: %v", err)
+	}
+	routing := New().
+		Add(`^/news`, newsRoutes).
+		Add(`^/admin`, adminRoutes).
+		MapError(errForbidden, http.StatusForbidden)
+
+	testCases := []struct {
+		name string
+		path string
+
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:               "no error",
+			path:               "/news/ok",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "ok",
+		}, {
+			name:               "mapped wrapped sentinel error",
+			path:               "/news/missing",
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       "Not Found\n",
+		}, {
+			name:               "error mapped by parent router",
+			path:               "/news/forbidden",
+			expectedStatusCode: http.StatusForbidden,
+			expectedBody:       "Forbidden\n",
+		}, {
+			name:               "http error with message",
+			path:               "/news/teapot",
+			expectedStatusCode: http.StatusTeapot,
+			expectedBody:       "short and stout\n",
+		}, {
+			name:               "http error takes precedence over mapping",
+			path:               "/news/conflict",
+			expectedStatusCode: http.StatusConflict,
+			expectedBody:       "Conflict\n",
+		}, {
+			name:               "unknown error",
+			path:               "/news/failure",
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       "Internal Server Error\n",
+		}, {
+			name:               "custom error handler",
+			path:               "/admin/failure",
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       "admin: db down",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "http://example.com"+tc.path, nil)
+			w := httptest.NewRecorder()
+
+			routing.ServeHTTP(w, req)
+
+			if tc.expectedStatusCode != w.Code {
+				t.Errorf("Expected status code '%d', but got '%d'", tc.expectedStatusCode, w.Code)
+			}
+			if tc.expectedBody != w.Body.String() {
+				t.Errorf("Expected body '%s', but got '%s'", tc.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package route
+
+import (
+	"context"
+	"net/http"
+)
+
+var hooksKey = "_hooks_"
+
+// Hooks are called on routing decisions of router and its sub routers, nil hooks are skipped.
+type Hooks struct {
+	// OnMatch is called when request is matched to route with handler, before middlewares run.
+	OnMatch func(r *http.Request, route RouteInfo, params map[string]string)
+	// OnNotFound is called when no route matches the request.
+	OnNotFound func(r *http.Request)
+	// OnMethodNotAllowed is called when route matches, but doesn't allow request method.
+	OnMethodNotAllowed func(r *http.Request, route RouteInfo)
+}
+
+// hooksChain is hooks of routers handling the request, from the innermost to the outermost one.
+type hooksChain struct {
+	hooks  Hooks
+	parent *hooksChain
+}
+
+func (r RegexpRouter) withHooks(req *http.Request) *http.Request {
+	if r.Hooks.OnMatch == nil && r.Hooks.OnNotFound == nil && r.Hooks.OnMethodNotAllowed == nil {
+		return req
+	}
+	parent, _ := req.Context().Value(hooksKey).(*hooksChain)
+	chain := &hooksChain{hooks: r.Hooks, parent: parent}
+	return req.WithContext(context.WithValue(req.Context(), hooksKey, chain))
+}
+
+// runHooks calls hooks of routers handling the request, starting with the outermost one.
+func runHooks(req *http.Request, fn func(hooks Hooks)) {
+	chain, _ := req.Context().Value(hooksKey).(*hooksChain)
+	var chains []*hooksChain
+	for ; chain != nil; chain = chain.parent {
+		chains = append(chains, chain)
+	}
+	for i := len(chains) - 1; i >= 0; i-- {
+		fn(chains[i].hooks)
+	}
+}
+
+func onMatch(req *http.Request, route RouteInfo) {
+	runHooks(req, func(hooks Hooks) {
+		if hooks.OnMatch != nil {
+			hooks.OnMatch(req, route, GetParams(req))
+		}
+	})
+}
+
+func onNotFound(req *http.Request) {
+	runHooks(req, func(hooks Hooks) {
+		if hooks.OnNotFound != nil {
+			hooks.OnNotFound(req)
+		}
+	})
+}
+
+func onMethodNotAllowed(req *http.Request, route RouteInfo) {
+	runHooks(req, func(hooks Hooks) {
+		if hooks.OnMethodNotAllowed != nil {
+			hooks.OnMethodNotAllowed(req, route)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package route
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestHooks(t *testing.T) {
+	var events []string
+	hooks := func(name string) Hooks {
+		return Hooks{
+			OnMatch: func(r *http.Request, route RouteInfo, params map[string]string) {
+				events = append(events, fmt.Sprintf("%s match %s %s %v", name, route.Meta.Name, route.Pattern, params))
+			},
+			OnNotFound: func(r *http.Request) {
+				events = append(events, fmt.Sprintf("%s not found %s", name, r.URL.Path))
+			},
+			OnMethodNotAllowed: func(r *http.Request, route RouteInfo) {
+				events = append(events, fmt.Sprintf("%s method not allowed %s %v", name, route.Pattern, route.Methods))
+			},
+		}
+	}
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+	newsRoutes := New().AddWithMeta(`^/(?P<pk>\d+)$`, noop, Meta{Name: "news-detail"}, http.MethodGet)
+	newsRoutes.Hooks = hooks("news")
+	versions := NewVersionRouter("v1").Add("v1", New().Add(`^/items$`, noop))
+	routing := New().
+		Add(`^/news`, newsRoutes).
+		Add(`^/api`, versions).
+		Add(`^/$`, noop)
+	routing.Hooks = hooks("root")
+
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+
+		expectedEvents []string
+	}{
+		{
+			name:           "match",
+			method:         http.MethodGet,
+			path:           "/",
+			expectedEvents: []string{"root match  ^/$ map[]"},
+		}, {
+			name:   "match in sub router",
+			method: http.MethodGet,
+			path:   "/news/12",
+			expectedEvents: []string{
+				"root match news-detail ^/(?P<pk>\\d+)$ map[pk:12]",
+				"news match news-detail ^/(?P<pk>\\d+)$ map[pk:12]",
+			},
+		}, {
+			name:           "not found",
+			method:         http.MethodGet,
+			path:           "/blog",
+			expectedEvents: []string{"root not found /blog"},
+		}, {
+			name:           "not found in sub router",
+			method:         http.MethodGet,
+			path:           "/news/abc",
+			expectedEvents: []string{"root not found /news/abc", "news not found /news/abc"},
+		}, {
+			name:           "not found version",
+			method:         http.MethodGet,
+			path:           "/api/v2/items",
+			expectedEvents: []string{"root not found /api/v2/items"},
+		}, {
+			name:           "match in version",
+			method:         http.MethodGet,
+			path:           "/api/v1/items",
+			expectedEvents: []string{"root match  ^/items$ map[]"},
+		}, {
+			name:           "method not allowed",
+			method:         http.MethodPost,
+			path:           "/news/12",
+			expectedEvents: []string{"root method not allowed ^/(?P<pk>\\d+)$ [GET]", "news method not allowed ^/(?P<pk>\\d+)$ [GET]"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			events = nil
+			req := httptest.NewRequest(tc.method, "http://example.com"+tc.path, nil)
+			w := httptest.NewRecorder()
+
+			routing.ServeHTTP(w, req)
+
+			if !reflect.DeepEqual(tc.expectedEvents, events) {
+				t.Errorf("Expected events '%q', but got '%q'", tc.expectedEvents, events)
+			}
+		})
+	}
+}
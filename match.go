@@ -0,0 +1,110 @@
+package route
+
+import (
+	"net/http"
+	"sort"
+)
+
+// Match describes result of resolving request against router, without calling any handler or middleware.
+type Match struct {
+	// Routes holds chain of matched routes, from the outermost router to the innermost one.
+	Routes []RouteInfo
+	Params map[string]string
+	// Status is http.StatusOK when route was found, http.StatusNotFound or http.StatusMethodNotAllowed otherwise.
+	Status int
+}
+
+type matcher interface {
+	match(req *http.Request, urlPath string, m *Match)
+	allowedMethods(req *http.Request, urlPath string) []string
+}
+
+// Match resolves request against router and its sub routers, it doesn't call any handler.
+func (r *RegexpRouter) Match(req *http.Request) Match {
+	m := Match{Params: map[string]string{}}
+	r.match(req, req.URL.Path, &m)
+	return m
+}
+
+func (r RegexpRouter) match(req *http.Request, urlPath string, m *Match) {
+	i, match, status := r.lookup(req.Method, urlPath)
+	m.Status = status
+	if status != http.StatusOK {
+		return
+	}
+	route := r.routes[i]
+	for i, name := range route.pattern.SubexpNames() {
+		if i != 0 {
+			m.Params[name] = match[i]
+		}
+	}
+	m.Routes = append(m.Routes, route.info())
+	if sub, ok := route.handler.(matcher); ok {
+		sub.match(req, route.pattern.ReplaceAllString(urlPath, ""), m)
+	}
+}
+
+// Match resolves request against router serving selected version, it doesn't call any handler.
+func (v *VersionRouter) Match(req *http.Request) Match {
+	m := Match{Params: map[string]string{}}
+	v.match(req, req.URL.Path, &m)
+	return m
+}
+
+func (v *VersionRouter) match(req *http.Request, urlPath string, m *Match) {
+	name, urlPath, fromPath := v.selectVersion(req, urlPath)
+	version, ok := v.versions[name]
+	if !ok {
+		m.Status = http.StatusNotFound
+		return
+	}
+	if fromPath {
+		m.Routes = append(m.Routes, versionRoute(name))
+	}
+	version.router.match(req, urlPath, m)
+}
+
+// AllowedMethods returns methods allowed for request's URL path by the innermost route matching it, resolved from
+// the router serving the request regardless of request method. It returns nil when no route matches the path.
+func AllowedMethods(r *http.Request) []string {
+	state := getMatch(r)
+	if state == nil || state.root == nil {
+		return nil
+	}
+	return state.root.allowedMethods(r, r.URL.Path)
+}
+
+func (r RegexpRouter) allowedMethods(req *http.Request, urlPath string) []string {
+	i, _, _ := r.lookup("", urlPath)
+	if i < 0 {
+		return nil
+	}
+	route := r.routes[i]
+	methods := route.info().Methods
+	if methods == nil {
+		for method := range defaultMethods {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+	}
+	sub, ok := route.handler.(matcher)
+	if !ok {
+		return methods
+	}
+	var allowed []string
+	for _, method := range sub.allowedMethods(req, route.pattern.ReplaceAllString(urlPath, "")) {
+		if _, ok := route.allowedMethods[method]; ok {
+			allowed = append(allowed, method)
+		}
+	}
+	return allowed
+}
+
+func (v *VersionRouter) allowedMethods(req *http.Request, urlPath string) []string {
+	name, urlPath, _ := v.selectVersion(req, urlPath)
+	version, ok := v.versions[name]
+	if !ok {
+		return nil
+	}
+	return version.router.allowedMethods(req, urlPath)
+}
@@ -0,0 +1,166 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"sort"
+)
+
+var matchKey = "_match_"
+
+// Meta holds arbitrary metadata attached to route at registration.
+type Meta struct {
+	Name        string
+	Description string
+	Tags        []string
+	Auth        string
+	RateLimit   string
+	Owner       string
+	Extra       map[string]interface{}
+}
+
+// HasTag reports whether given tag is attached to route.
+func (m Meta) HasTag(tag string) bool {
+	for _, t := range m.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// RouteInfo describes registered route. Methods are empty when route accepts any method. Methods and Meta are
+// shared with the router, so they must not be modified.
+type RouteInfo struct {
+	Pattern string
+	// Template is path template of the pattern, i.e. "/{pk}", or the pattern itself when it can't be converted.
+	Template string
+	Methods  []string
+	Meta     Meta
+}
+
+// matchState is shared by all routers handling the request, so routes matched deeper in the tree are visible
+// to middlewares of parent routers after the handler returns.
+type matchState struct {
+	routes []RouteInfo
+	// root is router serving the request, used to resolve AllowedMethods and GetTargetRoute.
+	root matcher
+	// target is whole chain of routes resolved by GetTargetRoute.
+	target *Match
+
+	hops        []hop
+	replay      []hop
+	uncacheable bool
+	cache       *matchCache
+	cacheKey    cacheKey
+	generation  uint64
+}
+
+func initMatch(r *http.Request) {
+	ctx := context.WithValue(r.Context(), matchKey, &matchState{})
+	(*r) = *(r.WithContext(ctx))
+}
+
+func getMatch(r *http.Request) *matchState {
+	state, _ := r.Context().Value(matchKey).(*matchState)
+	return state
+}
+
+func addMatchedRoute(r *http.Request, info RouteInfo) {
+	if state := getMatch(r); state != nil {
+		state.routes = append(state.routes, info)
+	}
+}
+
+// GetRoutes returns chain of routes matched so far, from the outermost router to the innermost one.
+func GetRoutes(r *http.Request) []RouteInfo {
+	state := getMatch(r)
+	if state == nil {
+		return nil
+	}
+	return append([]RouteInfo(nil), state.routes...)
+}
+
+// GetRoute returns innermost route matched so far, along with indicator if any route was matched. Middlewares of
+// parent router see the route sub router is mounted at, use GetTargetRoute to get the route handling the request.
+func GetRoute(r *http.Request) (RouteInfo, bool) {
+	state := getMatch(r)
+	if state == nil || len(state.routes) == 0 {
+		return RouteInfo{}, false
+	}
+	return state.routes[len(state.routes)-1], true
+}
+
+// GetTargetRoute returns innermost route request resolves to, through all sub routers, so middleware added to the
+// root router can decide based on metadata of the route that will handle the request. Chain is resolved once per
+// request, on the first call.
+func GetTargetRoute(r *http.Request) (RouteInfo, bool) {
+	state := getMatch(r)
+	if state == nil || state.root == nil {
+		return RouteInfo{}, false
+	}
+	if state.target == nil {
+		state.target = &Match{Params: map[string]string{}}
+		state.root.match(r, r.URL.Path, state.target)
+	}
+	if state.target.Status != http.StatusOK || len(state.target.Routes) == 0 {
+		return RouteInfo{}, false
+	}
+	return state.target.Routes[len(state.target.Routes)-1], true
+}
+
+// WalkFunc is called by Walk for every route that isn't a sub router, parents holds routes leading to it.
+type WalkFunc func(route RouteInfo, parents []RouteInfo) error
+
+type walker interface {
+	walk(parents []RouteInfo, fn WalkFunc) error
+}
+
+// Walk calls fn for each route registered in router and its sub routers, in registration order.
+// Walking stops at first error returned by fn.
+func (r *RegexpRouter) Walk(fn WalkFunc) error {
+	return r.walk(nil, fn)
+}
+
+func (r RegexpRouter) walk(parents []RouteInfo, fn WalkFunc) error {
+	for _, route := range r.routes {
+		info := route.info()
+		if sub, ok := route.handler.(walker); ok {
+			if err := sub.walk(appendRoute(parents, info), fn); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := fn(info, parents); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Walk calls fn for each route registered in every version, routes are prefixed with version path.
+func (v *VersionRouter) Walk(fn WalkFunc) error {
+	return v.walk(nil, fn)
+}
+
+func (v *VersionRouter) walk(parents []RouteInfo, fn WalkFunc) error {
+	var names []string
+	for name := range v.versions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := v.versions[name].router.walk(appendRoute(parents, versionRoute(name)), fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func versionRoute(name string) RouteInfo {
+	return RouteInfo{Pattern: "^/" + name, Template: "/" + name}
+}
+
+func appendRoute(parents []RouteInfo, info RouteInfo) []RouteInfo {
+	return append(append(make([]RouteInfo, 0, len(parents)+1), parents...), info)
+}
@@ -0,0 +1,120 @@
+package route
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestRouteMetaInContext(t *testing.T) {
+	var (
+		innerRoute, outerRoute, targetRoute RouteInfo
+		innerOk, outerOk, targetOk          bool
+		chain                               []RouteInfo
+	)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		innerRoute, innerOk = GetRoute(r)
+	}
+	newsRoutes := New().AddWithMeta(`^/(?P<pk>\d+)$`, handler, Meta{Name: "news-detail", Tags: []string{"public"}}, http.MethodGet)
+	routing := New().AddWithMeta(`^/news`, newsRoutes, Meta{Owner: "news-team"})
+	routing.AddMiddleware(func(fn http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if route, _ := GetRoute(r); route.Meta.Owner != "news-team" {
+				t.Errorf("Expected parent middleware to see parent route, got '%+v'", route)
+			}
+			targetRoute, targetOk = GetTargetRoute(r)
+			fn(w, r)
+			outerRoute, outerOk = GetRoute(r)
+			chain = GetRoutes(r)
+		}
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/news/12", nil)
+	routing.ServeHTTP(httptest.NewRecorder(), req)
+
+	expected := RouteInfo{
+		Pattern:  `^/(?P<pk>\d+)$`,
+		Template: "/{pk}",
+		Methods:  []string{http.MethodGet},
+		Meta:     Meta{Name: "news-detail", Tags: []string{"public"}},
+	}
+	if !innerOk || !reflect.DeepEqual(expected, innerRoute) {
+		t.Errorf("Expected handler to see route '%+v', got '%+v'", expected, innerRoute)
+	}
+	if !outerOk || !reflect.DeepEqual(expected, outerRoute) {
+		t.Errorf("Expected parent middleware to see matched route '%+v' after handler, got '%+v'", expected, outerRoute)
+	}
+	if !targetOk || !reflect.DeepEqual(expected, targetRoute) {
+		t.Errorf("Expected parent middleware to see target route '%+v' before handler, got '%+v'", expected, targetRoute)
+	}
+	if len(chain) != 2 || chain[0].Pattern != `^/news` {
+		t.Errorf("Expected chain of two routes, got '%+v'", chain)
+	}
+	if !innerRoute.Meta.HasTag("public") || innerRoute.Meta.HasTag("private") {
+		t.Errorf("Unexpected result of HasTag for tags '%v'", innerRoute.Meta.Tags)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+	v1 := New().AddWithMeta(`^/items$`, noop, Meta{Name: "items"})
+	newsRoutes := New().
+		AddWithMeta(`^/$`, noop, Meta{Name: "news-list"}, http.MethodGet).
+		AddWithMeta(`^/(?P<pk>\d+)$`, noop, Meta{Name: "news-detail"}, http.MethodGet, http.MethodPost)
+	routing := New().
+		Add(`^/news`, newsRoutes).
+		Add(`^/api`, NewVersionRouter("v1").Add("v1", v1)).
+		AddWithMeta(`^/$`, noop, Meta{Name: "index"})
+
+	var walked [][]string
+	err := routing.Walk(func(route RouteInfo, parents []RouteInfo) error {
+		var patterns []string
+		for _, parent := range parents {
+			patterns = append(patterns, parent.Pattern)
+		}
+		walked = append(walked, append(patterns, route.Pattern, route.Meta.Name))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	expected := [][]string{
+		{`^/news`, `^/$`, "news-list"},
+		{`^/news`, `^/(?P<pk>\d+)$`, "news-detail"},
+		{`^/api`, `^/v1`, `^/items$`, "items"},
+		{`^/$`, "index"},
+	}
+	if !reflect.DeepEqual(expected, walked) {
+		t.Errorf("Expected walked routes '%v', got '%v'", expected, walked)
+	}
+
+	errStop := errors.New("stop")
+	calls := 0
+	err = routing.Walk(func(route RouteInfo, parents []RouteInfo) error {
+		calls++
+		return errStop
+	})
+	if err != errStop || calls != 1 {
+		t.Errorf("Expected walk to stop on first error, got '%v' after %d calls", err, calls)
+	}
+}
+
+func TestGetTargetRouteNotFound(t *testing.T) {
+	var ok bool
+	routing := New().Add(`^/news`, New().Add(`^/(?P<pk>\d+)$`, func(w http.ResponseWriter, r *http.Request) {}))
+	routing.AddMiddleware(func(fn http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			_, ok = GetTargetRoute(r)
+			fn(w, r)
+		}
+	})
+
+	routing.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://example.com/news/abc", nil))
+
+	if ok {
+		t.Error("Expected no target route for path not matched by sub router")
+	}
+}
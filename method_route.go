@@ -0,0 +1,88 @@
+package route
+
+import (
+	"net/http"
+	"regexp"
+)
+
+// methodHandler dispatches request to handler registered for its method, HEAD falls back to GET handler.
+type methodHandler map[string]Handler
+
+func (h methodHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
+
+func (h methodHandler) handle(w http.ResponseWriter, r *http.Request) {
+	handler, ok := h[r.Method]
+	if !ok && r.Method == http.MethodHead {
+		handler = h[http.MethodGet]
+	}
+	handler.handle(w, r)
+}
+
+// MethodRoute registers separate handlers for different methods of a single pattern, requests with other
+// methods are answered with 405 listing registered methods. HEAD requests are served by GET handler, unless
+// HEAD handler is registered.
+type MethodRoute struct {
+	router *RegexpRouter
+	index  int
+}
+
+// Route adds route for given pattern, handlers for methods are registered on returned MethodRoute, i.e.
+// r.Route(`^/items/(?P<id>\d+)$`).Get(show).Put(update).Delete(remove).
+func (r *RegexpRouter) Route(pattern string) *MethodRoute {
+	route := newRoute(regexp.MustCompile(pattern), methodHandler{}, Meta{})
+	route.allowedMethods = map[string]struct{}{}
+	route.anyMethod = false
+	route.updateInfo()
+	r.routes = append(r.routes, route)
+	routesChanged()
+	return &MethodRoute{router: r, index: len(r.routes) - 1}
+}
+
+// Method registers handler for given method.
+func (m *MethodRoute) Method(method string, handler interface{}) *MethodRoute {
+	route := &m.router.routes[m.index]
+	route.handler.(methodHandler)[method] = toHandler(handler)
+	route.allowedMethods[method] = struct{}{}
+	if method == http.MethodGet {
+		route.allowedMethods[http.MethodHead] = struct{}{}
+	}
+	route.updateInfo()
+	routesChanged()
+	return m
+}
+
+// Meta attaches metadata to the route.
+func (m *MethodRoute) Meta(meta Meta) *MethodRoute {
+	route := &m.router.routes[m.index]
+	route.meta = meta
+	route.updateInfo()
+	return m
+}
+
+func (m *MethodRoute) Get(handler interface{}) *MethodRoute {
+	return m.Method(http.MethodGet, handler)
+}
+
+func (m *MethodRoute) Head(handler interface{}) *MethodRoute {
+	return m.Method(http.MethodHead, handler)
+}
+
+func (m *MethodRoute) Post(handler interface{}) *MethodRoute {
+	return m.Method(http.MethodPost, handler)
+}
+
+func (m *MethodRoute) Put(handler interface{}) *MethodRoute {
+	return m.Method(http.MethodPut, handler)
+}
+
+func (m *MethodRoute) Patch(handler interface{}) *MethodRoute {
+	return m.Method(http.MethodPatch, handler)
+}
+
+func (m *MethodRoute) Delete(handler interface{}) *MethodRoute {
+	return m.Method(http.MethodDelete, handler)
+}
+
+func (m *MethodRoute) Options(handler interface{}) *MethodRoute {
+	return m.Method(http.MethodOptions, handler)
+}
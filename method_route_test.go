@@ -0,0 +1,122 @@
+package route
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMethodRoute(t *testing.T) {
+	handler := func(name string) func(w http.ResponseWriter, r *http.Request) {
+		return func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, "%s %s", name, GetParams(r)["id"])
+		}
+	}
+	routing := New()
+	routing.Route(`^/items/(?P<id>\d+)$`).
+		Get(handler("show")).
+		Put(http.HandlerFunc(handler("update"))).
+		Delete(handler("remove")).
+		Meta(Meta{Name: "item"})
+	routing.Add(`^/other$`, handler("other"), http.MethodPost, http.MethodGet)
+	routing.Route(`^/empty$`).Meta(Meta{Name: "empty"})
+	routing.AddWithMeta(`^/all$`, handler("all"), Meta{Name: "all"}, http.MethodGet, http.MethodHead, http.MethodPost,
+		http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace)
+	routing.Route(`^/head$`).Head(handler("head")).Get(handler("get"))
+
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+
+		expectedStatusCode int
+		expectedBody       string
+		expectedAllow      string
+	}{
+		{
+			name:               "get",
+			method:             http.MethodGet,
+			path:               "/items/12",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "show 12",
+		}, {
+			name:               "put",
+			method:             http.MethodPut,
+			path:               "/items/12",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "update 12",
+		}, {
+			name:               "delete",
+			method:             http.MethodDelete,
+			path:               "/items/12",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "remove 12",
+		}, {
+			name:               "not registered method",
+			method:             http.MethodPost,
+			path:               "/items/12",
+			expectedStatusCode: http.StatusMethodNotAllowed,
+			expectedBody:       "Method Not Allowed\n",
+			expectedAllow:      "DELETE, GET, HEAD, PUT",
+		}, {
+			name:               "head served by get handler",
+			method:             http.MethodHead,
+			path:               "/items/12",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "show 12",
+		}, {
+			name:               "head handler",
+			method:             http.MethodHead,
+			path:               "/head",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "head ",
+		}, {
+			name:               "route without methods",
+			method:             http.MethodGet,
+			path:               "/empty",
+			expectedStatusCode: http.StatusMethodNotAllowed,
+			expectedBody:       "Method Not Allowed\n",
+		}, {
+			name:               "allow header on route added with methods",
+			method:             http.MethodDelete,
+			path:               "/other",
+			expectedStatusCode: http.StatusMethodNotAllowed,
+			expectedBody:       "Method Not Allowed\n",
+			expectedAllow:      "GET, POST",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "http://example.com"+tc.path, nil)
+			w := httptest.NewRecorder()
+
+			routing.ServeHTTP(w, req)
+
+			if tc.expectedStatusCode != w.Code {
+				t.Errorf("Expected status code '%d', but got '%d'", tc.expectedStatusCode, w.Code)
+			}
+			if tc.expectedBody != w.Body.String() {
+				t.Errorf("Expected body '%s', but got '%s'", tc.expectedBody, w.Body.String())
+			}
+			if allow := w.Header().Get("Allow"); tc.expectedAllow != allow {
+				t.Errorf("Expected Allow header '%s', but got '%s'", tc.expectedAllow, allow)
+			}
+		})
+	}
+
+	infos := map[string]RouteInfo{}
+	routing.Walk(func(route RouteInfo, parents []RouteInfo) error {
+		infos[route.Meta.Name] = route
+		return nil
+	})
+	if fmt.Sprint(infos["item"].Methods) != "[DELETE GET HEAD PUT]" {
+		t.Errorf("Expected walked route to have methods '[DELETE GET HEAD PUT]', got '%v'", infos["item"].Methods)
+	}
+	if methods := infos["empty"].Methods; methods == nil || len(methods) != 0 {
+		t.Errorf("Expected route without handlers to have no methods, got '%#v'", methods)
+	}
+	if methods := infos["all"].Methods; len(methods) != 9 {
+		t.Errorf("Expected route added with all methods to list them, got '%v'", methods)
+	}
+}
@@ -0,0 +1,191 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// CommonLogFormat is NCSA Common Log Format.
+	CommonLogFormat = `%h %l %u %t "%r" %>s %b`
+	// CombinedLogFormat is NCSA Combined Log Format.
+	CombinedLogFormat = CommonLogFormat + ` "%{Referer}i" "%{User-Agent}i"`
+)
+
+type logEntry struct {
+	req      *http.Request
+	w        ResponseWriter
+	status   int
+	start    time.Time
+	duration time.Duration
+}
+
+type logSegment func(b *strings.Builder, e *logEntry)
+
+// AccessLog writes line for every request to w using format similar to Apache's LogFormat. Supported directives
+// are %h, %l, %u, %t, %r, %s, %>s, %b, %B, %D, %T, %m, %U, %q, %H, %{Header}i, %{Header}o and %%, other ones are
+// written as they are.
+func AccessLog(w io.Writer, format string) func(f http.HandlerFunc) http.HandlerFunc {
+	segments := parseLogFormat(format)
+	var mu sync.Mutex
+	return func(f http.HandlerFunc) http.HandlerFunc {
+		return func(rw http.ResponseWriter, req *http.Request) {
+			entry := &logEntry{req: req, w: WrapResponseWriter(rw), start: time.Now()}
+
+			f(entry.w, req)
+
+			entry.duration = time.Since(entry.start)
+			entry.status = responseStatus(entry.w)
+			var b strings.Builder
+			for _, segment := range segments {
+				segment(&b, entry)
+			}
+			b.WriteString("\n")
+			mu.Lock()
+			io.WriteString(w, b.String())
+			mu.Unlock()
+		}
+	}
+}
+
+func parseLogFormat(format string) []logSegment {
+	var segments []logSegment
+	literal := func(s string) {
+		segments = append(segments, func(b *strings.Builder, e *logEntry) {
+			b.WriteString(s)
+		})
+	}
+	for {
+		i := strings.IndexByte(format, '%')
+		if i < 0 || i == len(format)-1 {
+			literal(format)
+			return segments
+		}
+		literal(format[:i])
+		format = format[i+1:]
+
+		var arg string
+		if format[0] == '{' {
+			end := strings.IndexByte(format, '}')
+			if end < 0 || end == len(format)-1 {
+				literal("%" + format)
+				return segments
+			}
+			arg, format = format[1:end], format[end+1:]
+		}
+		if format[0] == '>' && len(format) > 1 {
+			format = format[1:]
+		}
+		if segment := logDirective(format[0], arg); segment != nil {
+			segments = append(segments, segment)
+		} else if arg != "" {
+			literal("%{" + arg + "}" + format[:1])
+		} else {
+			literal("%" + format[:1])
+		}
+		format = format[1:]
+	}
+}
+
+func logDirective(directive byte, arg string) logSegment {
+	switch directive {
+	case '%':
+		return func(b *strings.Builder, e *logEntry) { b.WriteString("%") }
+	case 'h':
+		return func(b *strings.Builder, e *logEntry) {
+			host, _, err := net.SplitHostPort(e.req.RemoteAddr)
+			if err != nil {
+				host = e.req.RemoteAddr
+			}
+			b.WriteString(dash(host))
+		}
+	case 'l':
+		return func(b *strings.Builder, e *logEntry) { b.WriteString("-") }
+	case 'u':
+		return func(b *strings.Builder, e *logEntry) {
+			user, _ := GetUser(e.req)
+			b.WriteString(dash(escapeLogValue(user)))
+		}
+	case 't':
+		return func(b *strings.Builder, e *logEntry) {
+			b.WriteString(e.start.Format("[02/Jan/2006:15:04:05 -0700]"))
+		}
+	case 'r':
+		return func(b *strings.Builder, e *logEntry) {
+			b.WriteString(escapeLogValue(fmt.Sprintf("%s %s %s", e.req.Method, e.req.RequestURI, e.req.Proto)))
+		}
+	case 's':
+		return func(b *strings.Builder, e *logEntry) { b.WriteString(strconv.Itoa(e.status)) }
+	case 'b':
+		return func(b *strings.Builder, e *logEntry) {
+			if e.w.BytesWritten() == 0 {
+				b.WriteString("-")
+				return
+			}
+			b.WriteString(strconv.FormatInt(e.w.BytesWritten(), 10))
+		}
+	case 'B':
+		return func(b *strings.Builder, e *logEntry) { b.WriteString(strconv.FormatInt(e.w.BytesWritten(), 10)) }
+	case 'D':
+		return func(b *strings.Builder, e *logEntry) {
+			b.WriteString(strconv.FormatInt(e.duration.Microseconds(), 10))
+		}
+	case 'T':
+		return func(b *strings.Builder, e *logEntry) {
+			b.WriteString(strconv.FormatInt(int64(e.duration/time.Second), 10))
+		}
+	case 'm':
+		return func(b *strings.Builder, e *logEntry) { b.WriteString(escapeLogValue(e.req.Method)) }
+	case 'U':
+		return func(b *strings.Builder, e *logEntry) { b.WriteString(escapeLogValue(e.req.URL.Path)) }
+	case 'q':
+		return func(b *strings.Builder, e *logEntry) {
+			if e.req.URL.RawQuery != "" {
+				b.WriteString("?" + escapeLogValue(e.req.URL.RawQuery))
+			}
+		}
+	case 'H':
+		return func(b *strings.Builder, e *logEntry) { b.WriteString(e.req.Proto) }
+	case 'i':
+		if arg == "" {
+			return nil
+		}
+		return func(b *strings.Builder, e *logEntry) { b.WriteString(dash(escapeLogValue(e.req.Header.Get(arg)))) }
+	case 'o':
+		if arg == "" {
+			return nil
+		}
+		return func(b *strings.Builder, e *logEntry) { b.WriteString(dash(escapeLogValue(e.w.Header().Get(arg)))) }
+	}
+	return nil
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// escapeLogValue escapes quotes, backslashes and control characters, so values can't forge log lines.
+func escapeLogValue(s string) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 0x20 || c == 0x7f:
+			fmt.Fprintf(&b, "\\x%02x", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
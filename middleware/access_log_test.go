@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/Alkemic/go-route"
+)
+
+func TestAccessLog(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Cache", "hit")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("response"))
+	}
+	empty := func(w http.ResponseWriter, r *http.Request) {}
+	authenticated := BasicAuthenticate(log.New(io.Discard, "", 0), Authenticate("frank", "pass"), "realm")
+
+	testCases := []struct {
+		name    string
+		format  string
+		handler http.HandlerFunc
+		auth    bool
+
+		expectedLine string
+	}{
+		{
+			name:         "common",
+			format:       CommonLogFormat,
+			handler:      handler,
+			expectedLine: `^192\.0\.2\.1 - - \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] "GET /news/12\?page=2 HTTP/1\.1" 201 8` + "\n$",
+		}, {
+			name:         "combined with user",
+			format:       CombinedLogFormat,
+			handler:      authenticated(handler),
+			auth:         true,
+			expectedLine: `^192\.0\.2\.1 - frank \[.+\] "GET /news/12\?page=2 HTTP/1\.1" 201 8 "http://example\.com/" "agent \\"quoted\\""` + "\n$",
+		}, {
+			name:         "empty response",
+			format:       `%s %b %B`,
+			handler:      empty,
+			expectedLine: "^200 - 0\n$",
+		}, {
+			name:         "custom format",
+			format:       `%m %U%q %H %{X-Cache}o %{X-Missing}i %D %T %% %z %{foo}z`,
+			handler:      handler,
+			expectedLine: `^GET /news/12\?page=2 HTTP/1\.1 hit - \d+ 0 % %z %\{foo\}z` + "\n$",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			routing := route.New().Add(`^/news/(?P<pk>\d+)$`, tc.handler)
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/news/12?page=2", nil)
+			req.RequestURI = "/news/12?page=2"
+			req.Header.Set("Referer", "http://example.com/")
+			req.Header.Set("User-Agent", `agent "quoted"`)
+			if tc.auth {
+				req.SetBasicAuth("frank", "pass")
+			}
+
+			AccessLog(&buffer, tc.format)(routing.ServeHTTP)(httptest.NewRecorder(), req)
+
+			if !regexp.MustCompile(tc.expectedLine).MatchString(buffer.String()) {
+				t.Errorf("Expected line matching '%s', but got '%s'", tc.expectedLine, buffer.String())
+			}
+		})
+	}
+}
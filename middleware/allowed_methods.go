@@ -2,6 +2,8 @@ package middleware
 
 import (
 	"net/http"
+
+	"github.com/Alkemic/go-route"
 )
 
 func AllowedMethods(allowedMethods []string) func(f http.HandlerFunc) http.HandlerFunc {
@@ -14,7 +16,7 @@ func AllowedMethods(allowedMethods []string) func(f http.HandlerFunc) http.Handl
 				}
 			}
 
-			http.Error(rw, "405 method not allowed", http.StatusMethodNotAllowed)
+			route.RenderError(rw, req, http.StatusMethodNotAllowed, "405 method not allowed")
 		}
 	}
 }
@@ -36,8 +36,7 @@ func BasicAuthenticate(logger *log.Logger, authFn AuthFn, realm string) func(htt
 			userID, err := authFn(user, password)
 			if err != nil {
 				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Basic realm="%s"`, realm))
-				w.WriteHeader(401)
-				w.Write([]byte("401 Unauthorized\n"))
+				route.RenderError(w, r, http.StatusUnauthorized, "401 Unauthorized")
 				return
 			}
 
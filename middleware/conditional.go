@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"regexp"
+)
+
+// Predicate tells if conditional middleware should be applied to request.
+type Predicate func(r *http.Request) bool
+
+// PathMatches returns predicate matching requests which URL path matches any of given regexps.
+func PathMatches(patterns ...string) Predicate {
+	regexps := make([]*regexp.Regexp, len(patterns))
+	for i, pattern := range patterns {
+		regexps[i] = regexp.MustCompile(pattern)
+	}
+	return func(r *http.Request) bool {
+		for _, re := range regexps {
+			if re.MatchString(r.URL.Path) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MethodIs returns predicate matching requests with any of given methods.
+func MethodIs(methods ...string) Predicate {
+	return func(r *http.Request) bool {
+		for _, method := range methods {
+			if r.Method == method {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// When applies mw only to requests matching pred, other requests go straight to the handler.
+func When(pred Predicate, mw func(http.HandlerFunc) http.HandlerFunc) func(f http.HandlerFunc) http.HandlerFunc {
+	return func(f http.HandlerFunc) http.HandlerFunc {
+		wrapped := mw(f)
+		return func(rw http.ResponseWriter, req *http.Request) {
+			if pred(req) {
+				wrapped(rw, req)
+				return
+			}
+			f(rw, req)
+		}
+	}
+}
+
+// Unless applies mw only to requests not matching pred.
+func Unless(pred Predicate, mw func(http.HandlerFunc) http.HandlerFunc) func(f http.HandlerFunc) http.HandlerFunc {
+	return When(func(r *http.Request) bool {
+		return !pred(r)
+	}, mw)
+}
+
+// ForPaths applies mw only to requests which URL path matches any of given regexps.
+func ForPaths(patterns []string, mw func(http.HandlerFunc) http.HandlerFunc) func(f http.HandlerFunc) http.HandlerFunc {
+	return When(PathMatches(patterns...), mw)
+}
+
+// ForMethods applies mw only to requests with any of given methods.
+func ForMethods(methods []string, mw func(http.HandlerFunc) http.HandlerFunc) func(f http.HandlerFunc) http.HandlerFunc {
+	return When(MethodIs(methods...), mw)
+}
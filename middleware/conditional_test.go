@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConditional(t *testing.T) {
+	marker := SetHeaders(map[string]string{"X-Applied": "1"})
+	handler := func(rw http.ResponseWriter, req *http.Request) {}
+
+	testCases := []struct {
+		name       string
+		middleware func(http.HandlerFunc) http.HandlerFunc
+		method     string
+		path       string
+
+		expectedApplied bool
+	}{
+		{
+			name:            "when matches",
+			middleware:      When(func(r *http.Request) bool { return r.Header.Get("X-Test") == "" }, marker),
+			method:          http.MethodGet,
+			path:            "/",
+			expectedApplied: true,
+		}, {
+			name:            "when doesn't match",
+			middleware:      When(func(r *http.Request) bool { return false }, marker),
+			method:          http.MethodGet,
+			path:            "/",
+			expectedApplied: false,
+		}, {
+			name:            "unless excluded path",
+			middleware:      Unless(PathMatches(`^/health$`), marker),
+			method:          http.MethodGet,
+			path:            "/health",
+			expectedApplied: false,
+		}, {
+			name:            "unless other path",
+			middleware:      Unless(PathMatches(`^/health$`), marker),
+			method:          http.MethodGet,
+			path:            "/news",
+			expectedApplied: true,
+		}, {
+			name:            "for paths",
+			middleware:      ForPaths([]string{`^/static/`, `\.css$`}, marker),
+			method:          http.MethodGet,
+			path:            "/app/main.css",
+			expectedApplied: true,
+		}, {
+			name:            "for paths not matching",
+			middleware:      ForPaths([]string{`^/static/`}, marker),
+			method:          http.MethodGet,
+			path:            "/news",
+			expectedApplied: false,
+		}, {
+			name:            "for methods",
+			middleware:      ForMethods([]string{http.MethodPost, http.MethodPut}, marker),
+			method:          http.MethodPut,
+			path:            "/",
+			expectedApplied: true,
+		}, {
+			name:            "for methods not matching",
+			middleware:      ForMethods([]string{http.MethodPost, http.MethodPut}, marker),
+			method:          http.MethodGet,
+			path:            "/",
+			expectedApplied: false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "http://example.com"+tc.path, nil)
+			w := httptest.NewRecorder()
+
+			tc.middleware(handler)(w, req)
+
+			if applied := w.Header().Get("X-Applied") == "1"; applied != tc.expectedApplied {
+				t.Errorf("Expected middleware applied to be '%t', but got '%t'", tc.expectedApplied, applied)
+			}
+		})
+	}
+}
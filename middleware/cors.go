@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Alkemic/go-route"
+)
+
+// CORSOptions configures CORS.
+type CORSOptions struct {
+	// AllowedOrigins are origins allowed to make requests, exact ("https://example.com"), with wildcard subdomain
+	// ("https://*.example.com") or "*" allowing any origin.
+	AllowedOrigins []string
+	// AllowOrigin is called for origins not listed in AllowedOrigins.
+	AllowOrigin func(origin string) bool
+	// AllowCredentials allows requests with cookies and authorization, it can't be combined with "*" origin, use
+	// AllowOrigin to allow credentialed requests from origins that can't be listed.
+	AllowCredentials bool
+	// AllowedHeaders are request headers allowed in preflight, when empty headers requested by client are allowed.
+	AllowedHeaders []string
+	// ExposedHeaders are response headers exposed to client.
+	ExposedHeaders []string
+	// MaxAge tells how long preflight response may be cached.
+	MaxAge time.Duration
+}
+
+func (o CORSOptions) allowed(origin string) bool {
+	for _, allowed := range o.AllowedOrigins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+		if i := strings.Index(allowed, "*."); i >= 0 {
+			prefix, suffix := allowed[:i], allowed[i+1:]
+			if len(origin) > len(prefix)+len(suffix) && strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+				return true
+			}
+		}
+	}
+	return o.AllowOrigin != nil && o.AllowOrigin(origin)
+}
+
+func (o CORSOptions) anyOrigin() bool {
+	for _, allowed := range o.AllowedOrigins {
+		if allowed == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// CORS handles cross-origin requests. Preflight requests are answered with methods allowed by route matching
+// request path, so middleware should be added to the router owning the routes or to its parent. It panics when
+// "*" origin is combined with AllowCredentials, as any site could make requests with user's credentials.
+func CORS(opts CORSOptions) func(f http.HandlerFunc) http.HandlerFunc {
+	// response doesn't depend on origin only when any origin is allowed, which is done without credentials
+	wildcard := opts.anyOrigin()
+	if wildcard && opts.AllowCredentials {
+		panic(`cors: "*" origin can't be used with AllowCredentials`)
+	}
+	return func(f http.HandlerFunc) http.HandlerFunc {
+		return func(rw http.ResponseWriter, req *http.Request) {
+			preflight := req.Method == http.MethodOptions && req.Header.Get("Access-Control-Request-Method") != ""
+			if !wildcard {
+				rw.Header().Add("Vary", "Origin")
+			}
+			if preflight {
+				rw.Header().Add("Vary", "Access-Control-Request-Method")
+				rw.Header().Add("Vary", "Access-Control-Request-Headers")
+			}
+			origin := req.Header.Get("Origin")
+			if origin == "" || !opts.allowed(origin) {
+				if preflight && origin != "" {
+					rw.WriteHeader(http.StatusNoContent)
+					return
+				}
+				f(rw, req)
+				return
+			}
+
+			if wildcard {
+				rw.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				rw.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+			if opts.AllowCredentials {
+				rw.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+			if !preflight {
+				if len(opts.ExposedHeaders) > 0 {
+					rw.Header().Set("Access-Control-Expose-Headers", strings.Join(opts.ExposedHeaders, ", "))
+				}
+				f(rw, req)
+				return
+			}
+
+			methods := route.AllowedMethods(req)
+			if methods == nil {
+				f(rw, req)
+				return
+			}
+			rw.Header().Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+			if len(opts.AllowedHeaders) > 0 {
+				rw.Header().Set("Access-Control-Allow-Headers", strings.Join(opts.AllowedHeaders, ", "))
+			} else if headers := req.Header.Get("Access-Control-Request-Headers"); headers != "" {
+				rw.Header().Set("Access-Control-Allow-Headers", headers)
+			}
+			if opts.MaxAge > 0 {
+				rw.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(opts.MaxAge/time.Second)))
+			}
+			rw.WriteHeader(http.StatusNoContent)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Alkemic/go-route"
+)
+
+func TestCORS(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("response ok"))
+	}
+	newRouter := func(opts CORSOptions) *route.RegexpRouter {
+		apiRoutes := route.New().Add(`^/users$`, handler, http.MethodGet, http.MethodDelete)
+		return route.New().
+			Add(`^/items$`, handler, http.MethodGet, http.MethodPost).
+			Add(`^/api`, apiRoutes).
+			AddMiddleware(CORS(opts))
+	}
+	options := CORSOptions{
+		AllowedOrigins: []string{"https://example.com", "https://*.example.org"},
+		AllowOrigin: func(origin string) bool {
+			return strings.HasSuffix(origin, ".test")
+		},
+		AllowCredentials: true,
+		ExposedHeaders:   []string{"X-Total"},
+		MaxAge:           10 * time.Minute,
+	}
+
+	testCases := []struct {
+		name    string
+		opts    CORSOptions
+		method  string
+		path    string
+		headers map[string]string
+
+		expectedStatusCode int
+		expectedHeaders    map[string]string
+		expectedVary       []string
+	}{
+		{
+			name:               "preflight",
+			opts:               options,
+			method:             http.MethodOptions,
+			path:               "/items",
+			headers:            map[string]string{"Origin": "https://example.com", "Access-Control-Request-Method": "POST", "Access-Control-Request-Headers": "X-Token"},
+			expectedStatusCode: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://example.com",
+				"Access-Control-Allow-Methods":     "GET, POST",
+				"Access-Control-Allow-Headers":     "X-Token",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Max-Age":           "600",
+			},
+			expectedVary: []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		}, {
+			name:               "preflight to sub router",
+			opts:               options,
+			method:             http.MethodOptions,
+			path:               "/api/users",
+			headers:            map[string]string{"Origin": "https://shop.example.org", "Access-Control-Request-Method": "DELETE"},
+			expectedStatusCode: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":  "https://shop.example.org",
+				"Access-Control-Allow-Methods": "DELETE, GET",
+			},
+			expectedVary: []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		}, {
+			name:               "preflight from disallowed origin",
+			opts:               options,
+			method:             http.MethodOptions,
+			path:               "/items",
+			headers:            map[string]string{"Origin": "https://evil.com", "Access-Control-Request-Method": "POST"},
+			expectedStatusCode: http.StatusNoContent,
+			expectedHeaders:    map[string]string{"Access-Control-Allow-Origin": "", "Access-Control-Allow-Methods": ""},
+			expectedVary:       []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		}, {
+			name:               "options without preflight",
+			opts:               options,
+			method:             http.MethodOptions,
+			path:               "/items",
+			expectedStatusCode: http.StatusMethodNotAllowed,
+			expectedHeaders:    map[string]string{"Allow": "GET, POST", "Access-Control-Allow-Origin": ""},
+		}, {
+			name:               "actual request",
+			opts:               options,
+			method:             http.MethodGet,
+			path:               "/items",
+			headers:            map[string]string{"Origin": "http://app.test"},
+			expectedStatusCode: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "http://app.test",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Expose-Headers":    "X-Total",
+				"Access-Control-Allow-Methods":     "",
+			},
+			expectedVary: []string{"Origin"},
+		}, {
+			name:               "disallowed origin",
+			opts:               options,
+			method:             http.MethodGet,
+			path:               "/items",
+			headers:            map[string]string{"Origin": "https://example.org"},
+			expectedStatusCode: http.StatusOK,
+			expectedHeaders:    map[string]string{"Access-Control-Allow-Origin": ""},
+			expectedVary:       []string{"Origin"},
+		}, {
+			name:               "any origin",
+			opts:               CORSOptions{AllowedOrigins: []string{"*"}},
+			method:             http.MethodGet,
+			path:               "/items",
+			headers:            map[string]string{"Origin": "https://example.org"},
+			expectedStatusCode: http.StatusOK,
+			expectedHeaders:    map[string]string{"Access-Control-Allow-Origin": "*", "Access-Control-Allow-Credentials": ""},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "http://example.com"+tc.path, nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+
+			newRouter(tc.opts).ServeHTTP(w, req)
+
+			if tc.expectedStatusCode != w.Code {
+				t.Errorf("Expected status code '%d', but got '%d'", tc.expectedStatusCode, w.Code)
+			}
+			for k, v := range tc.expectedHeaders {
+				if h := w.Header().Get(k); h != v {
+					t.Errorf("Expected header '%s' to be '%s', but got '%s'", k, v, h)
+				}
+			}
+			if vary := w.Header()["Vary"]; !reflect.DeepEqual(tc.expectedVary, vary) {
+				t.Errorf("Expected Vary '%v', but got '%v'", tc.expectedVary, vary)
+			}
+		})
+	}
+}
+
+func TestCORSPanicsOnWildcardWithCredentials(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected CORS to panic when \"*\" origin is used with credentials")
+		}
+	}()
+	CORS(CORSOptions{AllowedOrigins: []string{"*"}, AllowCredentials: true})
+}
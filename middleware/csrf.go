@@ -0,0 +1,207 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+
+	"github.com/Alkemic/go-route"
+)
+
+var (
+	csrfKey = "_csrf_"
+
+	ErrCSRFTokenMissing   = errors.New("CSRF token missing")
+	ErrCSRFTokenInvalid   = errors.New("CSRF token invalid")
+	ErrCSRFOriginMismatch = errors.New("CSRF origin mismatch")
+)
+
+// CSRFOptions configures CSRF.
+type CSRFOptions struct {
+	// CookieName is name of cookie holding token, "_csrf" by default.
+	CookieName string
+	// HeaderName is request header token is read from, "X-CSRF-Token" by default.
+	HeaderName string
+	// FieldName is form field token is read from when header is missing, "csrf_token" by default.
+	FieldName string
+	// Secure marks cookie as sent only over HTTPS.
+	Secure bool
+
+	// ExemptRoutes are names of routes (Meta.Name) not checked, i.e. webhook endpoints.
+	ExemptRoutes []string
+	// ExemptPaths are regexps of URL paths not checked.
+	ExemptPaths []string
+	// Origin is origin application is served at, i.e. "https://example.com", by default it's request host with https
+	// scheme when request came over TLS and http otherwise. Set it when TLS is terminated by proxy.
+	Origin string
+	// TrustedOrigins are origins, besides request's own one, allowed in Origin and Referer headers.
+	TrustedOrigins []string
+
+	// FailureHandler answers rejected requests, by default 403 is rendered with RenderError.
+	FailureHandler func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type csrfToken struct {
+	token string
+	field string
+}
+
+var safeMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+}
+
+func csrfFailure(w http.ResponseWriter, r *http.Request, err error) {
+	route.RenderError(w, r, http.StatusForbidden, "403 forbidden: "+err.Error())
+}
+
+// CSRF protects from cross-site request forgery using double submit cookie. Token is kept in cookie, and requests
+// with unsafe methods must send it back in header or form field, and come from the same origin or a trusted one.
+// Exempt routes are matched by name, so middleware should be added to the router owning them.
+func CSRF(opts CSRFOptions) func(f http.HandlerFunc) http.HandlerFunc {
+	if opts.CookieName == "" {
+		opts.CookieName = "_csrf"
+	}
+	if opts.HeaderName == "" {
+		opts.HeaderName = "X-CSRF-Token"
+	}
+	if opts.FieldName == "" {
+		opts.FieldName = "csrf_token"
+	}
+	if opts.FailureHandler == nil {
+		opts.FailureHandler = csrfFailure
+	}
+	exemptPaths := PathMatches(opts.ExemptPaths...)
+	return func(f http.HandlerFunc) http.HandlerFunc {
+		return func(rw http.ResponseWriter, req *http.Request) {
+			if exemptPaths(req) || csrfExemptRoute(req, opts.ExemptRoutes) {
+				f(rw, req)
+				return
+			}
+
+			var token string
+			if cookie, err := req.Cookie(opts.CookieName); err == nil && validCSRFToken(cookie.Value) {
+				token = cookie.Value
+			}
+			cookieToken := token
+			if token == "" {
+				token = newCSRFToken()
+				http.SetCookie(rw, &http.Cookie{
+					Name:     opts.CookieName,
+					Value:    token,
+					Path:     "/",
+					Secure:   opts.Secure,
+					HttpOnly: true,
+					SameSite: http.SameSiteLaxMode,
+				})
+			}
+			rw.Header().Add("Vary", "Cookie")
+			req = req.WithContext(context.WithValue(req.Context(), csrfKey, csrfToken{token: token, field: opts.FieldName}))
+
+			if !safeMethods[req.Method] {
+				if err := checkCSRF(req, cookieToken, opts); err != nil {
+					opts.FailureHandler(rw, req, err)
+					return
+				}
+			}
+			f(rw, req)
+		}
+	}
+}
+
+func checkCSRF(req *http.Request, cookieToken string, opts CSRFOptions) error {
+	if !sameOrigin(req, opts.Origin, opts.TrustedOrigins) {
+		return ErrCSRFOriginMismatch
+	}
+	if cookieToken == "" {
+		return ErrCSRFTokenMissing
+	}
+	submitted := req.Header.Get(opts.HeaderName)
+	if submitted == "" {
+		submitted = req.PostFormValue(opts.FieldName)
+	}
+	if submitted == "" {
+		return ErrCSRFTokenMissing
+	}
+	if subtle.ConstantTimeCompare([]byte(submitted), []byte(cookieToken)) != 1 {
+		return ErrCSRFTokenInvalid
+	}
+	return nil
+}
+
+// sameOrigin checks Origin header, or Referer when it's missing, against request's own origin and trusted ones,
+// comparing both scheme and host. Requests without both headers are allowed, token check still applies to them.
+func sameOrigin(req *http.Request, own string, trusted []string) bool {
+	source := req.Header.Get("Origin")
+	if source == "" || source == "null" {
+		source = req.Header.Get("Referer")
+	}
+	if source == "" {
+		return req.Header.Get("Origin") != "null"
+	}
+	u, err := url.Parse(source)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	if own == "" {
+		own = "http://" + req.Host
+		if req.TLS != nil {
+			own = "https://" + req.Host
+		}
+	}
+	origin := u.Scheme + "://" + u.Host
+	if origin == own {
+		return true
+	}
+	for _, t := range trusted {
+		if t == origin {
+			return true
+		}
+	}
+	return false
+}
+
+func csrfExemptRoute(req *http.Request, names []string) bool {
+	for _, info := range route.GetRoutes(req) {
+		for _, name := range names {
+			if info.Meta.Name != "" && info.Meta.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func newCSRFToken() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func validCSRFToken(token string) bool {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	return err == nil && len(b) == 32
+}
+
+// CSRFToken returns CSRF token of the request, to be sent back in form field or header.
+func CSRFToken(r *http.Request) string {
+	token, _ := r.Context().Value(csrfKey).(csrfToken)
+	return token.token
+}
+
+// CSRFField returns hidden form input holding CSRF token, to be used in templates.
+func CSRFField(r *http.Request) template.HTML {
+	token, ok := r.Context().Value(csrfKey).(csrfToken)
+	if !ok {
+		return ""
+	}
+	return template.HTML(`<input type="hidden" name="` + template.HTMLEscapeString(token.field) + `" value="` +
+		template.HTMLEscapeString(token.token) + `">`)
+}
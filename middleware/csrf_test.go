@@ -0,0 +1,201 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Alkemic/go-route"
+)
+
+func TestCSRF(t *testing.T) {
+	const token = "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"
+	var failure error
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(CSRFField(r)))
+	}
+	newRouter := func(origin string, failureHandler func(http.ResponseWriter, *http.Request, error)) *route.RegexpRouter {
+		return route.New().
+			Add(`^/form$`, handler).
+			AddWithMeta(`^/webhook$`, handler, route.Meta{Name: "webhook"}).
+			Add(`^/hooks/`, handler).
+			AddMiddleware(CSRF(CSRFOptions{
+				ExemptRoutes:   []string{"webhook"},
+				ExemptPaths:    []string{`^/hooks/`},
+				Origin:         origin,
+				TrustedOrigins: []string{"https://admin.example.com"},
+				FailureHandler: failureHandler,
+			}))
+	}
+
+	testCases := []struct {
+		name    string
+		method  string
+		url     string
+		origin  string
+		cookie  string
+		headers map[string]string
+		form    url.Values
+
+		expectedStatusCode int
+		expectedError      error
+		expectedNewCookie  bool
+	}{
+		{
+			name:               "safe method sets cookie",
+			method:             http.MethodGet,
+			url:                "http://example.com/form",
+			expectedStatusCode: http.StatusOK,
+			expectedNewCookie:  true,
+		}, {
+			name:               "valid header",
+			method:             http.MethodPost,
+			url:                "http://example.com/form",
+			cookie:             token,
+			headers:            map[string]string{"X-CSRF-Token": token, "Origin": "http://example.com"},
+			expectedStatusCode: http.StatusOK,
+		}, {
+			name:               "valid form field",
+			method:             http.MethodPost,
+			url:                "http://example.com/form",
+			cookie:             token,
+			headers:            map[string]string{"Referer": "https://admin.example.com/page"},
+			form:               url.Values{"csrf_token": {token}},
+			expectedStatusCode: http.StatusOK,
+		}, {
+			name:               "missing cookie",
+			method:             http.MethodPost,
+			url:                "http://example.com/form",
+			headers:            map[string]string{"X-CSRF-Token": token},
+			expectedStatusCode: http.StatusForbidden,
+			expectedError:      ErrCSRFTokenMissing,
+			expectedNewCookie:  true,
+		}, {
+			name:               "missing token",
+			method:             http.MethodPost,
+			url:                "http://example.com/form",
+			cookie:             token,
+			expectedStatusCode: http.StatusForbidden,
+			expectedError:      ErrCSRFTokenMissing,
+		}, {
+			name:               "invalid token",
+			method:             http.MethodDelete,
+			url:                "http://example.com/form",
+			cookie:             token,
+			headers:            map[string]string{"X-CSRF-Token": "BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB"},
+			expectedStatusCode: http.StatusForbidden,
+			expectedError:      ErrCSRFTokenInvalid,
+		}, {
+			name:               "cross origin",
+			method:             http.MethodPost,
+			url:                "http://example.com/form",
+			cookie:             token,
+			headers:            map[string]string{"X-CSRF-Token": token, "Origin": "https://evil.com"},
+			expectedStatusCode: http.StatusForbidden,
+			expectedError:      ErrCSRFOriginMismatch,
+		}, {
+			name:               "same origin over tls",
+			method:             http.MethodPost,
+			url:                "https://example.com/form",
+			cookie:             token,
+			headers:            map[string]string{"X-CSRF-Token": token, "Origin": "https://example.com"},
+			expectedStatusCode: http.StatusOK,
+		}, {
+			name:               "insecure origin of tls request",
+			method:             http.MethodPost,
+			url:                "https://example.com/form",
+			cookie:             token,
+			headers:            map[string]string{"X-CSRF-Token": token, "Referer": "http://example.com/form"},
+			expectedStatusCode: http.StatusForbidden,
+			expectedError:      ErrCSRFOriginMismatch,
+		}, {
+			name:               "configured origin",
+			method:             http.MethodPost,
+			url:                "http://example.com/form",
+			origin:             "https://example.com",
+			cookie:             token,
+			headers:            map[string]string{"X-CSRF-Token": token, "Origin": "https://example.com"},
+			expectedStatusCode: http.StatusOK,
+		}, {
+			name:               "insecure origin with configured origin",
+			method:             http.MethodPost,
+			url:                "http://example.com/form",
+			origin:             "https://example.com",
+			cookie:             token,
+			headers:            map[string]string{"X-CSRF-Token": token, "Origin": "http://example.com"},
+			expectedStatusCode: http.StatusForbidden,
+			expectedError:      ErrCSRFOriginMismatch,
+		}, {
+			name:               "exempt route",
+			method:             http.MethodPost,
+			url:                "http://example.com/webhook",
+			expectedStatusCode: http.StatusOK,
+		}, {
+			name:               "exempt path",
+			method:             http.MethodPost,
+			url:                "http://example.com/hooks/github",
+			expectedStatusCode: http.StatusOK,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			failure = nil
+			var body *strings.Reader
+			if tc.form != nil {
+				body = strings.NewReader(tc.form.Encode())
+			} else {
+				body = strings.NewReader("")
+			}
+			req := httptest.NewRequest(tc.method, tc.url, body)
+			if tc.form != nil {
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			}
+			if tc.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "_csrf", Value: tc.cookie})
+			}
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+
+			newRouter(tc.origin, func(w http.ResponseWriter, r *http.Request, err error) {
+				failure = err
+				csrfFailure(w, r, err)
+			}).ServeHTTP(w, req)
+
+			if tc.expectedStatusCode != w.Code {
+				t.Errorf("Expected status code '%d', but got '%d'", tc.expectedStatusCode, w.Code)
+			}
+			if !errors.Is(failure, tc.expectedError) {
+				t.Errorf("Expected error '%v', but got '%v'", tc.expectedError, failure)
+			}
+			newCookie := strings.HasPrefix(w.Header().Get("Set-Cookie"), "_csrf=")
+			if newCookie != tc.expectedNewCookie {
+				t.Errorf("Expected new cookie to be '%t', but got '%t'", tc.expectedNewCookie, newCookie)
+			}
+		})
+	}
+}
+
+func TestCSRFField(t *testing.T) {
+	var token, field string
+	handler := CSRF(CSRFOptions{FieldName: "token"})(func(w http.ResponseWriter, r *http.Request) {
+		token = CSRFToken(r)
+		field = string(CSRFField(r))
+	})
+	w := httptest.NewRecorder()
+
+	handler(w, httptest.NewRequest(http.MethodGet, "http://example.com/form", nil))
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Value != token || !cookies[0].HttpOnly {
+		t.Fatalf("Expected HttpOnly cookie with token '%s', but got '%v'", token, cookies)
+	}
+	expected := `<input type="hidden" name="token" value="` + token + `">`
+	if field != expected {
+		t.Errorf("Expected field '%s', but got '%s'", expected, field)
+	}
+}
@@ -3,10 +3,12 @@ package middleware
 import (
 	"log"
 	"net/http"
+
+	"github.com/Alkemic/go-route"
 )
 
-func internalServerError(w http.ResponseWriter, _ *http.Request) {
-	http.Error(w, "500 internal server error", http.StatusInternalServerError)
+func internalServerError(w http.ResponseWriter, r *http.Request) {
+	route.RenderError(w, r, http.StatusInternalServerError, "500 internal server error")
 }
 
 func panicDefer(rw http.ResponseWriter, req *http.Request, logger *log.Logger) {
@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Alkemic/go-route"
+)
+
+// DefaultBuckets are upper bounds, in seconds, of request duration histogram buckets.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+var knownMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodConnect: true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+}
+
+type metricLabels struct {
+	method string
+	status string
+	route  string
+}
+
+type metricSeries struct {
+	count   uint64
+	sum     float64
+	buckets []uint64
+}
+
+// Metrics counts requests and records their duration, labeled by method, status class and matched route template.
+type Metrics struct {
+	mu      sync.Mutex
+	buckets []float64
+	series  map[metricLabels]*metricSeries
+}
+
+// NewMetrics returns Metrics recording duration histograms with given buckets, DefaultBuckets are used when none
+// are given.
+func NewMetrics(buckets ...float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &Metrics{buckets: buckets, series: map[metricLabels]*metricSeries{}}
+}
+
+// Middleware records metrics of requests. Route is taken from the match, so it should be added to the root router,
+// or wrap it to record also requests not matching any route, labeled with "unmatched" route.
+func (m *Metrics) Middleware(f http.HandlerFunc) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		w := WrapResponseWriter(rw)
+
+		f(w, req)
+
+		status := responseStatus(w)
+		method := req.Method
+		if !knownMethods[method] {
+			method = "other"
+		}
+		template := route.GetRouteTemplate(req)
+		if template == "" {
+			template = "unmatched"
+		}
+		m.observe(metricLabels{method: method, status: fmt.Sprintf("%dxx", status/100), route: template}, time.Since(start))
+	}
+}
+
+func (m *Metrics) observe(labels metricLabels, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.series[labels]
+	if !ok {
+		s = &metricSeries{buckets: make([]uint64, len(m.buckets))}
+		m.series[labels] = s
+	}
+	seconds := duration.Seconds()
+	s.count++
+	s.sum += seconds
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			s.buckets[i]++
+		}
+	}
+}
+
+// Handler exposes metrics in Prometheus text exposition format.
+func (m *Metrics) Handler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+// WriteTo writes metrics in Prometheus text exposition format.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	labels := make([]metricLabels, 0, len(m.series))
+	series := make(map[metricLabels]metricSeries, len(m.series))
+	for l, s := range m.series {
+		labels = append(labels, l)
+		series[l] = metricSeries{count: s.count, sum: s.sum, buckets: append([]uint64(nil), s.buckets...)}
+	}
+	m.mu.Unlock()
+	sort.Slice(labels, func(i, j int) bool {
+		if labels[i].route != labels[j].route {
+			return labels[i].route < labels[j].route
+		}
+		if labels[i].method != labels[j].method {
+			return labels[i].method < labels[j].method
+		}
+		return labels[i].status < labels[j].status
+	})
+
+	var b strings.Builder
+	b.WriteString("# HELP http_requests_total Total number of HTTP requests.\n")
+	b.WriteString("# TYPE http_requests_total counter\n")
+	for _, l := range labels {
+		fmt.Fprintf(&b, "http_requests_total{%s} %d\n", l, series[l].count)
+	}
+	b.WriteString("# HELP http_request_duration_seconds Duration of HTTP requests in seconds.\n")
+	b.WriteString("# TYPE http_request_duration_seconds histogram\n")
+	for _, l := range labels {
+		s := series[l]
+		for i, bound := range m.buckets {
+			fmt.Fprintf(&b, "http_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n", l, formatFloat(bound), s.buckets[i])
+		}
+		fmt.Fprintf(&b, "http_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", l, s.count)
+		fmt.Fprintf(&b, "http_request_duration_seconds_sum{%s} %s\n", l, formatFloat(s.sum))
+		fmt.Fprintf(&b, "http_request_duration_seconds_count{%s} %d\n", l, s.count)
+	}
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func (l metricLabels) String() string {
+	return fmt.Sprintf(`method="%s",route="%s",status="%s"`, escapeLabel(l.method), escapeLabel(l.route), escapeLabel(l.status))
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
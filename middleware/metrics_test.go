@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Alkemic/go-route"
+)
+
+func TestMetrics(t *testing.T) {
+	metrics := NewMetrics(60, 0)
+	newsRoutes := route.New().Add(`^/(?P<pk>\d+)$`, func(w http.ResponseWriter, r *http.Request) {
+		if route.GetParams(r)["pk"] == "0" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}, http.MethodGet)
+	routing := route.New().
+		Add(`^/news`, newsRoutes).
+		Add(`^/metrics$`, metrics.Handler)
+	handler := metrics.Middleware(routing.ServeHTTP)
+
+	for _, path := range []string{"/news/1", "/news/2", "/news/0", "/blog/1"} {
+		handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://example.com"+path, nil))
+	}
+	handler(httptest.NewRecorder(), httptest.NewRequest("PURGE", "http://example.com/news/1", nil))
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/metrics", nil)
+	w := httptest.NewRecorder()
+	routing.ServeHTTP(w, req)
+
+	if ct := w.Header().Get("Content-Type"); ct != "text/plain; version=0.0.4; charset=utf-8" {
+		t.Errorf("Expected Content-Type 'text/plain; version=0.0.4; charset=utf-8', but got '%s'", ct)
+	}
+	body := w.Body.String()
+	expectedLines := []string{
+		"# TYPE http_requests_total counter",
+		`http_requests_total{method="GET",route="/news/{pk}",status="2xx"} 2`,
+		`http_requests_total{method="GET",route="/news/{pk}",status="4xx"} 1`,
+		`http_requests_total{method="other",route="unmatched",status="4xx"} 1`,
+		`http_requests_total{method="GET",route="unmatched",status="4xx"} 1`,
+		"# TYPE http_request_duration_seconds histogram",
+		`http_request_duration_seconds_bucket{method="GET",route="/news/{pk}",status="2xx",le="0"} 0`,
+		`http_request_duration_seconds_bucket{method="GET",route="/news/{pk}",status="2xx",le="60"} 2`,
+		`http_request_duration_seconds_bucket{method="GET",route="/news/{pk}",status="2xx",le="+Inf"} 2`,
+		`http_request_duration_seconds_count{method="GET",route="/news/{pk}",status="2xx"} 2`,
+	}
+	for _, line := range expectedLines {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("Expected line '%s' in metrics, but got '%s'", line, body)
+		}
+	}
+	if strings.Contains(body, "/blog/1") {
+		t.Errorf("Expected raw paths not to be used as labels, but got '%s'", body)
+	}
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Alkemic/go-route"
+)
+
+func TestProblemJSONErrors(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := log.New(io.Writer(&buffer), "", 0)
+
+	testCases := []struct {
+		name    string
+		handler http.HandlerFunc
+
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:               "allowed methods",
+			handler:            AllowedMethods([]string{http.MethodPost})(basicHandler),
+			expectedStatusCode: http.StatusMethodNotAllowed,
+			expectedBody:       `{"detail":"405 method not allowed","instance":"/foo","status":405,"title":"Method Not Allowed","type":"about:blank"}` + "\n",
+		}, {
+			name:               "basic authenticate",
+			handler:            BasicAuthenticate(logger, Authenticate("user", "pass"), "realm")(basicHandler),
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedBody:       `{"detail":"401 Unauthorized","instance":"/foo","status":401,"title":"Unauthorized","type":"about:blank"}` + "\n",
+		}, {
+			name:               "panic interceptor",
+			handler:            PanicInterceptorWithLogger(logger)(stringPanicHandler),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"detail":"500 internal server error","instance":"/foo","status":500,"title":"Internal Server Error","type":"about:blank"}` + "\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			routing := route.New().Add(`^/foo$`, tc.handler)
+			routing.ErrorRenderer = route.ProblemJSON(nil)
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/foo", nil)
+			w := httptest.NewRecorder()
+
+			routing.ServeHTTP(w, req)
+
+			if tc.expectedStatusCode != w.Code {
+				t.Errorf("Expected status code '%d', but got '%d'", tc.expectedStatusCode, w.Code)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "application/problem+json" {
+				t.Errorf("Expected Content-Type 'application/problem+json', but got '%s'", ct)
+			}
+			if tc.expectedBody != w.Body.String() {
+				t.Errorf("Expected body '%s', but got '%s'", tc.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package middleware
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// ResponseWriter is http.ResponseWriter recording status code, number of written bytes and time of the first
+// write. Writer returned by WrapResponseWriter implements http.Flusher, http.Hijacker, io.ReaderFrom and
+// http.Pusher only if the wrapped writer does.
+type ResponseWriter interface {
+	http.ResponseWriter
+
+	// Status returns status code sent to client, or 0 when nothing has been written yet.
+	Status() int
+	// BytesWritten returns number of body bytes written.
+	BytesWritten() int64
+	// FirstWrite returns time of the first write of headers or body, zero if nothing has been written yet.
+	FirstWrite() time.Time
+	// Unwrap returns wrapped writer, it's used by http.ResponseController.
+	Unwrap() http.ResponseWriter
+}
+
+// WrapResponseWriter wraps w into ResponseWriter, w is returned as is when it's already wrapped.
+func WrapResponseWriter(w http.ResponseWriter) ResponseWriter {
+	if rw, ok := w.(ResponseWriter); ok {
+		return rw
+	}
+	rw := &responseWriter{ResponseWriter: w}
+
+	f, isFlusher := w.(http.Flusher)
+	h, isHijacker := w.(http.Hijacker)
+	rf, isReaderFrom := w.(io.ReaderFrom)
+	p, isPusher := w.(http.Pusher)
+	fl := flusher{rw, f}
+	hj := hijacker{h}
+	rd := readerFrom{rw, rf}
+	ps := pusher{p}
+
+	switch {
+	case isFlusher && isHijacker && isReaderFrom && isPusher:
+		return struct {
+			*responseWriter
+			flusher
+			hijacker
+			readerFrom
+			pusher
+		}{rw, fl, hj, rd, ps}
+	case isFlusher && isHijacker && isReaderFrom:
+		return struct {
+			*responseWriter
+			flusher
+			hijacker
+			readerFrom
+		}{rw, fl, hj, rd}
+	case isFlusher && isHijacker && isPusher:
+		return struct {
+			*responseWriter
+			flusher
+			hijacker
+			pusher
+		}{rw, fl, hj, ps}
+	case isFlusher && isReaderFrom && isPusher:
+		return struct {
+			*responseWriter
+			flusher
+			readerFrom
+			pusher
+		}{rw, fl, rd, ps}
+	case isHijacker && isReaderFrom && isPusher:
+		return struct {
+			*responseWriter
+			hijacker
+			readerFrom
+			pusher
+		}{rw, hj, rd, ps}
+	case isFlusher && isHijacker:
+		return struct {
+			*responseWriter
+			flusher
+			hijacker
+		}{rw, fl, hj}
+	case isFlusher && isReaderFrom:
+		return struct {
+			*responseWriter
+			flusher
+			readerFrom
+		}{rw, fl, rd}
+	case isFlusher && isPusher:
+		return struct {
+			*responseWriter
+			flusher
+			pusher
+		}{rw, fl, ps}
+	case isHijacker && isReaderFrom:
+		return struct {
+			*responseWriter
+			hijacker
+			readerFrom
+		}{rw, hj, rd}
+	case isHijacker && isPusher:
+		return struct {
+			*responseWriter
+			hijacker
+			pusher
+		}{rw, hj, ps}
+	case isReaderFrom && isPusher:
+		return struct {
+			*responseWriter
+			readerFrom
+			pusher
+		}{rw, rd, ps}
+	case isFlusher:
+		return struct {
+			*responseWriter
+			flusher
+		}{rw, fl}
+	case isHijacker:
+		return struct {
+			*responseWriter
+			hijacker
+		}{rw, hj}
+	case isReaderFrom:
+		return struct {
+			*responseWriter
+			readerFrom
+		}{rw, rd}
+	case isPusher:
+		return struct {
+			*responseWriter
+			pusher
+		}{rw, ps}
+	}
+	return rw
+}
+
+type responseWriter struct {
+	http.ResponseWriter
+
+	status     int
+	bytes      int64
+	firstWrite time.Time
+}
+
+// responseStatus returns status code sent to client, or 200 which is sent by net/http when handler writes nothing.
+func responseStatus(w ResponseWriter) int {
+	if status := w.Status(); status != 0 {
+		return status
+	}
+	return http.StatusOK
+}
+
+func (w *responseWriter) Status() int {
+	return w.status
+}
+
+func (w *responseWriter) BytesWritten() int64 {
+	return w.bytes
+}
+
+func (w *responseWriter) FirstWrite() time.Time {
+	return w.firstWrite
+}
+
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if w.firstWrite.IsZero() {
+		w.firstWrite = time.Now()
+	}
+	// informational responses are followed by the final one, except for switching protocols
+	if w.status == 0 && (code >= 200 || code == http.StatusSwitchingProtocols) {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// writeHeader sends implicit 200 if handler hasn't written status code.
+func (w *responseWriter) writeHeader() {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.writeHeader()
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+type flusher struct {
+	rw *responseWriter
+	f  http.Flusher
+}
+
+func (f flusher) Flush() {
+	f.rw.writeHeader()
+	f.f.Flush()
+}
+
+type hijacker struct {
+	h http.Hijacker
+}
+
+func (h hijacker) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return h.h.Hijack()
+}
+
+type readerFrom struct {
+	rw *responseWriter
+	rf io.ReaderFrom
+}
+
+func (r readerFrom) ReadFrom(src io.Reader) (int64, error) {
+	r.rw.writeHeader()
+	n, err := r.rf.ReadFrom(src)
+	r.rw.bytes += n
+	return n, err
+}
+
+type pusher struct {
+	p http.Pusher
+}
+
+func (p pusher) Push(target string, opts *http.PushOptions) error {
+	return p.p.Push(target, opts)
+}
@@ -0,0 +1,237 @@
+package middleware
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type flushRecorder struct{ *httptest.ResponseRecorder }
+
+type hijackRecorder struct{ *httptest.ResponseRecorder }
+
+func (hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) { return nil, nil, nil }
+
+type readFromRecorder struct{ *httptest.ResponseRecorder }
+
+func (w readFromRecorder) ReadFrom(src io.Reader) (int64, error) { return io.Copy(w.Body, src) }
+
+type pushRecorder struct{ *httptest.ResponseRecorder }
+
+func (pushRecorder) Push(target string, opts *http.PushOptions) error { return nil }
+
+type plainRecorder struct{ w *httptest.ResponseRecorder }
+
+func (w plainRecorder) Header() http.Header         { return w.w.Header() }
+func (w plainRecorder) Write(b []byte) (int, error) { return w.w.Write(b) }
+func (w plainRecorder) WriteHeader(code int)        { w.w.WriteHeader(code) }
+
+type unwrapRecorder struct{ http.ResponseWriter }
+
+func (w unwrapRecorder) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+func TestWrapResponseWriterInterfaces(t *testing.T) {
+	testCases := []struct {
+		name   string
+		writer http.ResponseWriter
+
+		flusher    bool
+		hijacker   bool
+		readerFrom bool
+		pusher     bool
+	}{
+		{
+			name:   "plain",
+			writer: plainRecorder{httptest.NewRecorder()},
+		}, {
+			name:    "flusher",
+			writer:  flushRecorder{httptest.NewRecorder()},
+			flusher: true,
+		}, {
+			name:     "flusher and hijacker",
+			writer:   hijackRecorder{httptest.NewRecorder()},
+			flusher:  true,
+			hijacker: true,
+		}, {
+			name:       "flusher and reader from",
+			writer:     readFromRecorder{httptest.NewRecorder()},
+			flusher:    true,
+			readerFrom: true,
+		}, {
+			name:    "flusher and pusher",
+			writer:  pushRecorder{httptest.NewRecorder()},
+			flusher: true,
+			pusher:  true,
+		}, {
+			name: "all",
+			writer: struct {
+				hijackRecorder
+				readFromRecorder
+				pushRecorder
+				http.ResponseWriter
+				http.Flusher
+			}{ResponseWriter: httptest.NewRecorder(), Flusher: httptest.NewRecorder()},
+			flusher:    true,
+			hijacker:   true,
+			readerFrom: true,
+			pusher:     true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := WrapResponseWriter(tc.writer)
+
+			if _, ok := w.(http.Flusher); ok != tc.flusher {
+				t.Errorf("Expected http.Flusher to be '%t', but got '%t'", tc.flusher, ok)
+			}
+			if _, ok := w.(http.Hijacker); ok != tc.hijacker {
+				t.Errorf("Expected http.Hijacker to be '%t', but got '%t'", tc.hijacker, ok)
+			}
+			if _, ok := w.(io.ReaderFrom); ok != tc.readerFrom {
+				t.Errorf("Expected io.ReaderFrom to be '%t', but got '%t'", tc.readerFrom, ok)
+			}
+			if _, ok := w.(http.Pusher); ok != tc.pusher {
+				t.Errorf("Expected http.Pusher to be '%t', but got '%t'", tc.pusher, ok)
+			}
+			if w.Unwrap() != tc.writer {
+				t.Error("Expected Unwrap to return wrapped writer")
+			}
+			if WrapResponseWriter(w) != w {
+				t.Error("Expected already wrapped writer to be reused")
+			}
+		})
+	}
+}
+
+func TestWrapResponseWriterRecords(t *testing.T) {
+	testCases := []struct {
+		name  string
+		write func(w http.ResponseWriter)
+
+		expectedStatus int
+		expectedBytes  int64
+		expectedBody   string
+	}{
+		{
+			name:  "nothing written",
+			write: func(w http.ResponseWriter) {},
+		}, {
+			name: "implicit status",
+			write: func(w http.ResponseWriter) {
+				w.Write([]byte("foo"))
+				w.Write([]byte("bar"))
+			},
+			expectedStatus: http.StatusOK,
+			expectedBytes:  6,
+			expectedBody:   "foobar",
+		}, {
+			name: "explicit status",
+			write: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusNotFound)
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("foo"))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBytes:  3,
+			expectedBody:   "foo",
+		}, {
+			name: "informational status",
+			write: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusProcessing)
+				w.WriteHeader(http.StatusCreated)
+			},
+			expectedStatus: http.StatusCreated,
+		}, {
+			name: "flush",
+			write: func(w http.ResponseWriter) {
+				w.(http.Flusher).Flush()
+			},
+			expectedStatus: http.StatusOK,
+		}, {
+			name: "read from",
+			write: func(w http.ResponseWriter) {
+				w.(io.ReaderFrom).ReadFrom(strings.NewReader("foobar"))
+			},
+			expectedStatus: http.StatusOK,
+			expectedBytes:  6,
+			expectedBody:   "foobar",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			w := WrapResponseWriter(readFromRecorder{recorder})
+
+			tc.write(w)
+
+			if tc.expectedStatus != w.Status() {
+				t.Errorf("Expected status code '%d', but got '%d'", tc.expectedStatus, w.Status())
+			}
+			if tc.expectedBytes != w.BytesWritten() {
+				t.Errorf("Expected '%d' bytes written, but got '%d'", tc.expectedBytes, w.BytesWritten())
+			}
+			if tc.expectedBody != recorder.Body.String() {
+				t.Errorf("Expected body '%s', but got '%s'", tc.expectedBody, recorder.Body.String())
+			}
+			expectedResponseStatus := tc.expectedStatus
+			if expectedResponseStatus == 0 {
+				expectedResponseStatus = http.StatusOK
+			}
+			if status := responseStatus(w); expectedResponseStatus != status {
+				t.Errorf("Expected response status '%d', but got '%d'", expectedResponseStatus, status)
+			}
+			if (tc.expectedStatus == 0) != w.FirstWrite().IsZero() {
+				t.Errorf("Expected first write time to be set only after write, but got '%s'", w.FirstWrite())
+			}
+		})
+	}
+}
+
+func TestWrapResponseWriterResponseController(t *testing.T) {
+	testCases := []struct {
+		name   string
+		writer func(recorder *httptest.ResponseRecorder) http.ResponseWriter
+
+		expectedFlushErr  error
+		expectedHijackErr error
+		expectedFlushed   bool
+	}{
+		{
+			name:            "flusher and hijacker",
+			writer:          func(recorder *httptest.ResponseRecorder) http.ResponseWriter { return hijackRecorder{recorder} },
+			expectedFlushed: true,
+		}, {
+			name: "through other wrapper",
+			writer: func(recorder *httptest.ResponseRecorder) http.ResponseWriter {
+				return unwrapRecorder{hijackRecorder{recorder}}
+			},
+			expectedFlushed: true,
+		}, {
+			name:              "plain",
+			writer:            func(recorder *httptest.ResponseRecorder) http.ResponseWriter { return plainRecorder{recorder} },
+			expectedFlushErr:  http.ErrNotSupported,
+			expectedHijackErr: http.ErrNotSupported,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			controller := http.NewResponseController(WrapResponseWriter(tc.writer(recorder)))
+
+			if err := controller.Flush(); !errors.Is(err, tc.expectedFlushErr) {
+				t.Errorf("Expected flush error '%v', but got '%v'", tc.expectedFlushErr, err)
+			}
+			if _, _, err := controller.Hijack(); !errors.Is(err, tc.expectedHijackErr) {
+				t.Errorf("Expected hijack error '%v', but got '%v'", tc.expectedHijackErr, err)
+			}
+			if tc.expectedFlushed != recorder.Flushed {
+				t.Errorf("Expected flushed to be '%t', but got '%t'", tc.expectedFlushed, recorder.Flushed)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Alkemic/go-route"
+)
+
+var (
+	loggerKey    = "_logger_"
+	requestIDKey = "_request_id_"
+
+	// RequestIDHeader is header request ID is taken from, and returned in.
+	RequestIDHeader = "X-Request-ID"
+)
+
+// Fields logged by StructuredLog.
+const (
+	FieldMethod    = "method"
+	FieldPath      = "path"
+	FieldRoute     = "route"
+	FieldParams    = "params"
+	FieldStatus    = "status"
+	FieldBytes     = "bytes"
+	FieldDuration  = "duration"
+	FieldRemoteIP  = "remote_ip"
+	FieldUser      = "user"
+	FieldRequestID = "request_id"
+)
+
+// DefaultLogFields are fields logged when none are selected.
+var DefaultLogFields = []string{
+	FieldMethod, FieldPath, FieldRoute, FieldParams, FieldStatus, FieldBytes, FieldDuration, FieldRemoteIP, FieldUser,
+	FieldRequestID,
+}
+
+// LogOptions configures StructuredLog.
+type LogOptions struct {
+	// Message of log records, "request" by default.
+	Message string
+	// Fields selects logged fields, DefaultLogFields are used when empty.
+	Fields []string
+	// Level returns level of record for response status, by default 5xx are logged as errors, 4xx as warnings and
+	// everything else as info.
+	Level func(status int) slog.Level
+}
+
+// DefaultLevel logs 5xx responses as errors, 4xx as warnings and everything else as info.
+func DefaultLevel(status int) slog.Level {
+	switch {
+	case status >= 500:
+		return slog.LevelError
+	case status >= 400:
+		return slog.LevelWarn
+	}
+	return slog.LevelInfo
+}
+
+const maxRequestIDLength = 128
+
+// validRequestID tells if request ID sent by client can be logged and returned as is.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') && !strings.ContainsRune("-_.:+/=", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// StructuredLog logs every request using logger. Request ID is taken from RequestIDHeader when it's at most 128
+// characters of safe charset, otherwise it's generated, and it's returned in response header. Logger with request
+// ID attached is available to handlers through GetLogger.
+func StructuredLog(logger *slog.Logger, opts LogOptions) func(f http.HandlerFunc) http.HandlerFunc {
+	if opts.Message == "" {
+		opts.Message = "request"
+	}
+	if len(opts.Fields) == 0 {
+		opts.Fields = DefaultLogFields
+	}
+	if opts.Level == nil {
+		opts.Level = DefaultLevel
+	}
+	return func(f http.HandlerFunc) http.HandlerFunc {
+		return func(rw http.ResponseWriter, req *http.Request) {
+			start := time.Now()
+			requestID := req.Header.Get(RequestIDHeader)
+			if !validRequestID(requestID) {
+				requestID = randomHex(16)
+			}
+			rw.Header().Set(RequestIDHeader, requestID)
+			requestLogger := logger.With(FieldRequestID, requestID)
+			ctx := context.WithValue(req.Context(), requestIDKey, requestID)
+			ctx = context.WithValue(ctx, loggerKey, requestLogger)
+			req = req.WithContext(ctx)
+			w := WrapResponseWriter(rw)
+
+			f(w, req)
+
+			status := responseStatus(w)
+			attrs := make([]slog.Attr, 0, len(opts.Fields))
+			for _, field := range opts.Fields {
+				if attr, ok := logField(field, req, w, status, time.Since(start), requestID); ok {
+					attrs = append(attrs, attr)
+				}
+			}
+			logger.LogAttrs(req.Context(), opts.Level(status), opts.Message, attrs...)
+		}
+	}
+}
+
+func logField(field string, req *http.Request, w ResponseWriter, status int, duration time.Duration, requestID string) (slog.Attr, bool) {
+	switch field {
+	case FieldMethod:
+		return slog.String(field, req.Method), true
+	case FieldPath:
+		return slog.String(field, req.URL.Path), true
+	case FieldRoute:
+		return slog.String(field, route.GetRouteTemplate(req)), true
+	case FieldParams:
+		params := route.GetParams(req)
+		keys := make([]string, 0, len(params))
+		for key := range params {
+			if key != "" && key != UserKey {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		values := make([]interface{}, len(keys))
+		for i, key := range keys {
+			values[i] = slog.String(key, params[key])
+		}
+		return slog.Group(field, values...), len(values) > 0
+	case FieldStatus:
+		return slog.Int(field, status), true
+	case FieldBytes:
+		return slog.Int64(field, w.BytesWritten()), true
+	case FieldDuration:
+		return slog.Duration(field, duration), true
+	case FieldRemoteIP:
+		ip, _, err := net.SplitHostPort(req.RemoteAddr)
+		if err != nil {
+			ip = req.RemoteAddr
+		}
+		return slog.String(field, ip), true
+	case FieldUser:
+		user, err := GetUser(req)
+		return slog.String(field, user), err == nil
+	case FieldRequestID:
+		return slog.String(field, requestID), true
+	}
+	return slog.Attr{}, false
+}
+
+// GetLogger returns request scoped logger set by StructuredLog, or slog.Default.
+func GetLogger(r *http.Request) *slog.Logger {
+	if logger, ok := r.Context().Value(loggerKey).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// GetRequestID returns ID of the request set by StructuredLog.
+func GetRequestID(r *http.Request) string {
+	requestID, _ := r.Context().Value(requestIDKey).(string)
+	return requestID
+}
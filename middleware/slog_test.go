@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Alkemic/go-route"
+)
+
+func TestStructuredLog(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buffer, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == FieldDuration {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	newsRoutes := route.New().Add(`^/(?P<pk>\d+)$`, func(w http.ResponseWriter, r *http.Request) {
+		GetLogger(r).Info("handler", "pk", route.GetParams(r)["pk"])
+		if route.GetParams(r)["pk"] == "0" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		w.Write([]byte("news"))
+	}).AddMiddleware(BasicAuthenticate(log.New(io.Discard, "", 0), Authenticate("user", "pass"), "realm"))
+	routing := route.New().Add(`^/news`, newsRoutes)
+
+	testCases := []struct {
+		name      string
+		opts      LogOptions
+		path      string
+		requestID string
+
+		expectedRecords []map[string]interface{}
+	}{
+		{
+			name:      "all fields",
+			path:      "/news/12",
+			requestID: "abc",
+			expectedRecords: []map[string]interface{}{
+				{"level": "INFO", "msg": "handler", "request_id": "abc", "pk": "12"},
+				{
+					"level": "INFO", "msg": "request", "method": "GET", "path": "/news/12", "route": "/news/{pk}",
+					"params": map[string]interface{}{"pk": "12"}, "status": float64(200), "bytes": float64(4),
+					"remote_ip": "192.0.2.1", "user": "user", "request_id": "abc",
+				},
+			},
+		}, {
+			name:      "selected fields and level",
+			opts:      LogOptions{Message: "access", Fields: []string{FieldStatus, FieldRoute}},
+			path:      "/news/0",
+			requestID: "def",
+			expectedRecords: []map[string]interface{}{
+				{"level": "INFO", "msg": "handler", "request_id": "def", "pk": "0"},
+				{"level": "WARN", "msg": "access", "status": float64(404), "route": "/news/{pk}"},
+			},
+		}, {
+			name: "custom level",
+			opts: LogOptions{
+				Fields: []string{FieldStatus},
+				Level:  func(status int) slog.Level { return slog.LevelDebug - 1 },
+			},
+			path:      "/news/12",
+			requestID: "ghi",
+			expectedRecords: []map[string]interface{}{
+				{"level": "INFO", "msg": "handler", "request_id": "ghi", "pk": "12"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buffer.Reset()
+			req := httptest.NewRequest(http.MethodGet, "http://example.com"+tc.path, nil)
+			req.SetBasicAuth("user", "pass")
+			req.Header.Set(RequestIDHeader, tc.requestID)
+			w := httptest.NewRecorder()
+
+			StructuredLog(logger, tc.opts)(routing.ServeHTTP)(w, req)
+
+			if h := w.Header().Get(RequestIDHeader); h != tc.requestID {
+				t.Errorf("Expected header '%s' to be '%s', but got '%s'", RequestIDHeader, tc.requestID, h)
+			}
+			var records []map[string]interface{}
+			decoder := json.NewDecoder(&buffer)
+			for decoder.More() {
+				record := map[string]interface{}{}
+				if err := decoder.Decode(&record); err != nil {
+					t.Fatalf("Unexpected error: '%v'", err)
+				}
+				records = append(records, record)
+			}
+			if !reflect.DeepEqual(tc.expectedRecords, records) {
+				t.Errorf("Expected records '%v', but got '%v'", tc.expectedRecords, records)
+			}
+		})
+	}
+}
+
+func TestStructuredLogGeneratesRequestID(t *testing.T) {
+	testCases := []struct {
+		name      string
+		requestID string
+	}{
+		{name: "missing", requestID: ""},
+		{name: "too long", requestID: strings.Repeat("a", 129)},
+		{name: "unsafe characters", requestID: "abc\" forged=1"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var requestID string
+			handler := StructuredLog(slog.New(slog.NewTextHandler(io.Discard, nil)), LogOptions{})(func(w http.ResponseWriter, r *http.Request) {
+				requestID = GetRequestID(r)
+			})
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+			req.Header.Set(RequestIDHeader, tc.requestID)
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			if requestID == "" || requestID == tc.requestID || w.Header().Get(RequestIDHeader) != requestID {
+				t.Errorf("Expected generated request ID '%s' in header, but got '%s'", requestID, w.Header().Get(RequestIDHeader))
+			}
+		})
+	}
+}
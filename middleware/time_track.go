@@ -18,3 +18,65 @@ func TimeTrack(logger *log.Logger) func(f http.HandlerFunc) http.HandlerFunc {
 		}
 	}
 }
+
+// Timing describes time spent handling request.
+type Timing struct {
+	Method string
+	URI    string
+	Status int
+	Bytes  int64
+	// TTFB is time to the first write of headers or body, it equals Duration when nothing was written.
+	TTFB     time.Duration
+	Duration time.Duration
+	// Slow tells if request took longer than slow threshold.
+	Slow bool
+}
+
+// TimeTrackOptions configures TimeTrackWith.
+type TimeTrackOptions struct {
+	// Logger logs every request, standard logger is used when nil.
+	Logger *log.Logger
+	// SlowThreshold marks requests taking longer as slow, they are logged with "SLOW" prefix. Zero disables it.
+	SlowThreshold time.Duration
+	// Sink receives timing of every request, i.e. to feed metrics.
+	Sink func(r *http.Request, timing Timing)
+}
+
+// TimeTrackWith logs method, URI, status, bytes written, time to first byte and total time of every request.
+func TimeTrackWith(opts TimeTrackOptions) func(f http.HandlerFunc) http.HandlerFunc {
+	printf := log.Printf
+	if opts.Logger != nil {
+		printf = opts.Logger.Printf
+	}
+	return func(f http.HandlerFunc) http.HandlerFunc {
+		return func(rw http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			w := WrapResponseWriter(rw)
+
+			f(w, r)
+
+			timing := Timing{
+				Method:   r.Method,
+				URI:      r.RequestURI,
+				Status:   responseStatus(w),
+				Bytes:    w.BytesWritten(),
+				Duration: time.Since(start),
+			}
+			timing.TTFB = timing.Duration
+			if !w.FirstWrite().IsZero() {
+				timing.TTFB = w.FirstWrite().Sub(start)
+			}
+			timing.Slow = opts.SlowThreshold > 0 && timing.Duration > opts.SlowThreshold
+
+			prefix := ""
+			if timing.Slow {
+				prefix = "SLOW "
+			}
+			printf("%s%s %s %d %d bytes took %s (ttfb %s)", prefix, timing.Method, timing.URI, timing.Status,
+				timing.Bytes, timing.Duration, timing.TTFB)
+			if opts.Sink != nil {
+				opts.Sink(r, timing)
+			}
+		}
+	}
+}
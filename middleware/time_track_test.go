@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestTimeTrackWith(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte("response"))
+	}
+
+	testCases := []struct {
+		name      string
+		threshold time.Duration
+
+		expectedSlow bool
+		expectedLog  string
+	}{
+		{
+			name:        "fast request",
+			threshold:   time.Second,
+			expectedLog: `^GET /news/12 202 8 bytes took \S+ \(ttfb \S+\)` + "\n$",
+		}, {
+			name:         "slow request",
+			threshold:    10 * time.Millisecond,
+			expectedSlow: true,
+			expectedLog:  `^SLOW GET /news/12 202 8 bytes took \S+ \(ttfb \S+\)` + "\n$",
+		}, {
+			name:        "threshold disabled",
+			expectedLog: `^GET /news/12 202 8 bytes took \S+ \(ttfb \S+\)` + "\n$",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				buffer bytes.Buffer
+				timing Timing
+			)
+			middleware := TimeTrackWith(TimeTrackOptions{
+				Logger:        log.New(&buffer, "", 0),
+				SlowThreshold: tc.threshold,
+				Sink: func(r *http.Request, t Timing) {
+					timing = t
+				},
+			})
+			req := httptest.NewRequest(http.MethodGet, "/news/12", nil)
+
+			middleware(handler)(httptest.NewRecorder(), req)
+
+			if !regexp.MustCompile(tc.expectedLog).MatchString(buffer.String()) {
+				t.Errorf("Expected log matching '%s', but got '%s'", tc.expectedLog, buffer.String())
+			}
+			if timing.Status != http.StatusAccepted || timing.Bytes != 8 || timing.Slow != tc.expectedSlow {
+				t.Errorf("Expected status 202, 8 bytes and slow '%t', but got '%+v'", tc.expectedSlow, timing)
+			}
+			if timing.TTFB >= 20*time.Millisecond || timing.Duration < 20*time.Millisecond {
+				t.Errorf("Expected time to first byte to be measured before sleep, but got '%+v'", timing)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Alkemic/go-route"
+)
+
+var traceContextKey = "_trace_context_"
+
+// TraceContext is W3C trace context of a span.
+type TraceContext struct {
+	TraceID string
+	SpanID  string
+	Sampled bool
+	// State is vendor specific tracestate header, propagated as is.
+	State string
+}
+
+// ParseTraceparent parses traceparent header, it returns false when header is missing or invalid.
+func ParseTraceparent(traceparent string) (TraceContext, bool) {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 {
+		return TraceContext{}, false
+	}
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	if !isLowerHex(version, 2) || version == "ff" || (version == "00" && len(parts) != 4) {
+		return TraceContext{}, false
+	}
+	if !isLowerHex(traceID, 32) || !isLowerHex(spanID, 16) || !isLowerHex(flags, 2) {
+		return TraceContext{}, false
+	}
+	if strings.Trim(traceID, "0") == "" || strings.Trim(spanID, "0") == "" {
+		return TraceContext{}, false
+	}
+	sampled, _ := hex.DecodeString(flags)
+	return TraceContext{TraceID: traceID, SpanID: spanID, Sampled: sampled[0]&1 == 1}, true
+}
+
+func isLowerHex(s string, length int) bool {
+	if len(s) != length {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// Traceparent formats trace context as traceparent header.
+func (tc TraceContext) Traceparent() string {
+	flags := "00"
+	if tc.Sampled {
+		flags = "01"
+	}
+	return "00-" + tc.TraceID + "-" + tc.SpanID + "-" + flags
+}
+
+// Inject sets traceparent and tracestate headers, i.e. of outgoing request.
+func (tc TraceContext) Inject(header http.Header) {
+	header.Set("traceparent", tc.Traceparent())
+	if tc.State != "" {
+		header.Set("tracestate", tc.State)
+	} else {
+		header.Del("tracestate")
+	}
+}
+
+// GetTraceContext returns trace context of span of the request started by Tracing.
+func GetTraceContext(r *http.Request) (TraceContext, bool) {
+	tc, ok := r.Context().Value(traceContextKey).(TraceContext)
+	return tc, ok
+}
+
+// Span describes single handled request.
+type Span struct {
+	// Name is method and matched route template, i.e. "GET /news/{pk}".
+	Name         string
+	TraceContext TraceContext
+	// ParentSpanID is ID of span that sent the request, empty for root spans.
+	ParentSpanID string
+
+	Method string
+	Path   string
+	Route  string
+	Status int
+	Size   int64
+	Start  time.Time
+	End    time.Time
+}
+
+// SpanExporter receives finished spans.
+type SpanExporter interface {
+	Export(span Span)
+}
+
+// InMemoryExporter collects spans in memory, it's meant for tests.
+type InMemoryExporter struct {
+	mu    sync.Mutex
+	spans []Span
+}
+
+func (e *InMemoryExporter) Export(span Span) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, span)
+}
+
+// Spans returns exported spans.
+func (e *InMemoryExporter) Spans() []Span {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]Span(nil), e.spans...)
+}
+
+// Reset removes exported spans.
+func (e *InMemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = nil
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Tracing starts span for each request, continuing trace from traceparent and tracestate headers, and exports it
+// when the handler returns. Span is named after matched route template, so it should be added to the root router,
+// or wrap it to trace also requests not matching any route.
+func Tracing(exporter SpanExporter) func(f http.HandlerFunc) http.HandlerFunc {
+	return func(f http.HandlerFunc) http.HandlerFunc {
+		return func(rw http.ResponseWriter, req *http.Request) {
+			span := Span{Method: req.Method, Path: req.URL.Path, Start: time.Now()}
+			tc, ok := ParseTraceparent(req.Header.Get("traceparent"))
+			if ok {
+				span.ParentSpanID = tc.SpanID
+				tc.State = req.Header.Get("tracestate")
+			} else {
+				tc = TraceContext{TraceID: randomHex(16), Sampled: true}
+			}
+			tc.SpanID = randomHex(8)
+			span.TraceContext = tc
+
+			w := WrapResponseWriter(rw)
+			req = req.WithContext(context.WithValue(req.Context(), traceContextKey, tc))
+			defer func() {
+				span.End = time.Now()
+				span.Status = responseStatus(w)
+				span.Size = w.BytesWritten()
+				span.Route = route.GetRouteTemplate(req)
+				span.Name = span.Method
+				if span.Route != "" {
+					span.Name += " " + span.Route
+				}
+				exporter.Export(span)
+			}()
+
+			f(w, req)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Alkemic/go-route"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	testCases := []struct {
+		name        string
+		traceparent string
+
+		expectedOk      bool
+		expectedContext TraceContext
+	}{
+		{
+			name:            "sampled",
+			traceparent:     "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			expectedOk:      true,
+			expectedContext: TraceContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7", Sampled: true},
+		}, {
+			name:            "not sampled",
+			traceparent:     "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+			expectedOk:      true,
+			expectedContext: TraceContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"},
+		}, {
+			name:            "future version",
+			traceparent:     "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+			expectedOk:      true,
+			expectedContext: TraceContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7", Sampled: true},
+		}, {
+			name:        "empty",
+			traceparent: "",
+		}, {
+			name:        "upper case",
+			traceparent: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		}, {
+			name:        "zero trace id",
+			traceparent: "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		}, {
+			name:        "invalid version",
+			traceparent: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		}, {
+			name:        "extra fields in version 00",
+			traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			traceContext, ok := ParseTraceparent(tc.traceparent)
+			if ok != tc.expectedOk {
+				t.Fatalf("Expected ok to be '%t', but got '%t'", tc.expectedOk, ok)
+			}
+			if traceContext != tc.expectedContext {
+				t.Errorf("Expected trace context '%+v', but got '%+v'", tc.expectedContext, traceContext)
+			}
+		})
+	}
+}
+
+func TestTracing(t *testing.T) {
+	exporter := &InMemoryExporter{}
+	newsRoutes := route.New().Add(`^/(?P<pk>\d+)$`, func(w http.ResponseWriter, r *http.Request) {
+		tc, _ := GetTraceContext(r)
+		outgoing := http.Header{}
+		tc.Inject(outgoing)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, outgoing.Get("traceparent"), " ", outgoing.Get("tracestate"))
+	})
+	routing := route.New().
+		Add(`^/news`, newsRoutes).
+		AddMiddleware(Tracing(exporter))
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/news/12", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	req.Header.Set("tracestate", "vendor=value")
+	w := httptest.NewRecorder()
+
+	routing.ServeHTTP(w, req)
+
+	spans := exporter.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, but got '%d'", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "GET /news/{pk}" {
+		t.Errorf("Expected span name 'GET /news/{pk}', but got '%s'", span.Name)
+	}
+	if span.TraceContext.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Expected trace id to be propagated, but got '%s'", span.TraceContext.TraceID)
+	}
+	if span.ParentSpanID != "00f067aa0ba902b7" {
+		t.Errorf("Expected parent span id '00f067aa0ba902b7', but got '%s'", span.ParentSpanID)
+	}
+	if span.Status != http.StatusCreated {
+		t.Errorf("Expected status code '%d', but got '%d'", http.StatusCreated, span.Status)
+	}
+	if span.Size != int64(w.Body.Len()) {
+		t.Errorf("Expected size '%d', but got '%d'", w.Body.Len(), span.Size)
+	}
+	expectedBody := span.TraceContext.Traceparent() + " vendor=value"
+	if w.Body.String() != expectedBody {
+		t.Errorf("Expected body '%s', but got '%s'", expectedBody, w.Body.String())
+	}
+
+	exporter.Reset()
+	req = httptest.NewRequest(http.MethodGet, "http://example.com/blog", nil)
+	Tracing(exporter)(routing.ServeHTTP)(httptest.NewRecorder(), req)
+	spans = exporter.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, but got '%d'", len(spans))
+	}
+	if spans[0].Name != "GET" || spans[0].Status != http.StatusNotFound || spans[0].ParentSpanID != "" {
+		t.Errorf("Expected root span 'GET' with status 404, but got '%+v'", spans[0])
+	}
+}
@@ -0,0 +1,204 @@
+package route
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Renderer renders value into response body using its content type.
+type Renderer interface {
+	ContentType() string
+	Render(w io.Writer, v interface{}) error
+}
+
+type renderer struct {
+	contentType string
+	render      func(w io.Writer, v interface{}) error
+}
+
+func (r renderer) ContentType() string {
+	return r.contentType
+}
+
+func (r renderer) Render(w io.Writer, v interface{}) error {
+	return r.render(w, v)
+}
+
+// NewRenderer returns Renderer for given content type using provided function. Content type is sent as is, so it
+// should include charset parameter for text formats.
+func NewRenderer(contentType string, render func(w io.Writer, v interface{}) error) Renderer {
+	return renderer{contentType: contentType, render: render}
+}
+
+// JSON renders values as application/json.
+func JSON() Renderer {
+	return NewRenderer("application/json; charset=utf-8", func(w io.Writer, v interface{}) error {
+		return json.NewEncoder(w).Encode(v)
+	})
+}
+
+// XML renders values as application/xml.
+func XML() Renderer {
+	return NewRenderer("application/xml; charset=utf-8", func(w io.Writer, v interface{}) error {
+		if _, err := io.WriteString(w, xml.Header); err != nil {
+			return err
+		}
+		return xml.NewEncoder(w).Encode(v)
+	})
+}
+
+// Text renders values as text/plain using fmt.Fprint.
+func Text() Renderer {
+	return NewRenderer("text/plain; charset=utf-8", func(w io.Writer, v interface{}) error {
+		_, err := fmt.Fprint(w, v)
+		return err
+	})
+}
+
+// HTMLTemplate renders values as text/html by executing named template.
+func HTMLTemplate(tmpl *template.Template, name string) Renderer {
+	return NewRenderer("text/html; charset=utf-8", func(w io.Writer, v interface{}) error {
+		return tmpl.ExecuteTemplate(w, name, v)
+	})
+}
+
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+// parseAccept returns media ranges from Accept header, including ranges with q=0 which exclude matching types.
+func parseAccept(header string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(fields[0]))
+		if mediaType == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+			if len(kv) == 2 && strings.ToLower(kv[0]) == "q" {
+				if v, err := strconv.ParseFloat(kv[1], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		ranges = append(ranges, acceptRange{mediaType: mediaType, q: q})
+	}
+	return ranges
+}
+
+// matchMediaRange returns how specific media range matching content type is, -1 when it doesn't match. Exact type
+// is the most specific, then vendor type with structured syntax suffix (application/vnd.x.v2+json matches
+// application/json), type/* and */*.
+func matchMediaRange(mediaRange, contentType string) int {
+	switch {
+	case mediaRange == contentType:
+		return 3
+	case mediaRange == "*/*":
+		return 0
+	case strings.HasSuffix(mediaRange, "/*"):
+		if strings.HasPrefix(contentType, strings.TrimSuffix(mediaRange, "*")) {
+			return 1
+		}
+		return -1
+	}
+	// only vendor types, so application/xhtml+xml sent by browsers doesn't select XML over HTML
+	slash, plus := strings.IndexByte(mediaRange, '/'), strings.LastIndexByte(mediaRange, '+')
+	if slash >= 0 && plus > slash && strings.HasPrefix(mediaRange[slash+1:], "vnd.") &&
+		mediaRange[:slash+1]+mediaRange[plus+1:] == contentType {
+		return 2
+	}
+	return -1
+}
+
+// quality returns q-value of content type taken from the most specific matching range, 0 if none matches.
+func quality(ranges []acceptRange, contentType string) float64 {
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = contentType[:i]
+	}
+	contentType = strings.ToLower(strings.TrimSpace(contentType))
+	q, specificity := 0.0, -1
+	for _, r := range ranges {
+		if s := matchMediaRange(r.mediaType, contentType); s > specificity {
+			q, specificity = r.q, s
+		}
+	}
+	return q
+}
+
+// Negotiator chooses between registered renderers based on request's Accept header.
+type Negotiator struct {
+	renderers []Renderer
+}
+
+// NewNegotiator returns Negotiator choosing between given renderers, first one is used when client accepts anything.
+func NewNegotiator(renderers ...Renderer) *Negotiator {
+	return &Negotiator{renderers: renderers}
+}
+
+// Negotiate returns renderer best matching request's Accept header, and indicator if any renderer was acceptable.
+func (n *Negotiator) Negotiate(r *http.Request) (Renderer, bool) {
+	if len(n.renderers) == 0 {
+		return nil, false
+	}
+	header := r.Header.Get("Accept")
+	if header == "" {
+		return n.renderers[0], true
+	}
+	ranges := parseAccept(header)
+	var (
+		best  Renderer
+		bestQ float64
+	)
+	for _, renderer := range n.renderers {
+		if q := quality(ranges, renderer.ContentType()); q > bestQ {
+			best, bestQ = renderer, q
+		}
+	}
+	return best, best != nil
+}
+
+// Render writes v using negotiated renderer with given status code, answers 406 when nothing fits. Value is
+// rendered before anything is written, so rendering error is answered using HandleError, and returned.
+func (n *Negotiator) Render(w http.ResponseWriter, r *http.Request, status int, v interface{}) error {
+	renderer, ok := n.Negotiate(r)
+	w.Header().Add("Vary", "Accept")
+	if !ok {
+		RenderError(w, r, http.StatusNotAcceptable, "406 not acceptable")
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, v); err != nil {
+		HandleError(w, r, err)
+		return err
+	}
+	w.Header().Set("Content-Type", renderer.ContentType())
+	w.WriteHeader(status)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// ValueHandlerFunc is handler returning value that will be rendered using negotiated renderer.
+type ValueHandlerFunc func(w http.ResponseWriter, r *http.Request) (interface{}, error)
+
+// Handle returns http handler rendering value returned from fn, errors are answered using HandleError.
+func (n *Negotiator) Handle(fn ValueHandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		v, err := fn(w, r)
+		if err != nil {
+			HandleError(w, r, err)
+			return
+		}
+		n.Render(w, r, http.StatusOK, v)
+	}
+}
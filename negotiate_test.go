@@ -0,0 +1,164 @@
+package route
+
+import (
+	"errors"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNegotiator(t *testing.T) {
+	type news struct {
+		Title string `json:"title" xml:"title"`
+	}
+	tmpl := template.Must(template.New("news").Parse(`<h1>{{.Title}}</h1>`))
+	negotiator := NewNegotiator(JSON(), XML(), HTMLTemplate(tmpl, "news"), Text())
+	failingTmpl := template.Must(template.New("news").Parse(`<h1>{{.Missing}}</h1>`))
+	png := NewRenderer("image/png", func(w io.Writer, v interface{}) error {
+		_, err := io.WriteString(w, "png")
+		return err
+	})
+
+	okHandler := func(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+		return news{Title: "go"}, nil
+	}
+	errHandler := func(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+		return nil, errors.New("failure")
+	}
+
+	testCases := []struct {
+		name       string
+		negotiator *Negotiator
+		accept     string
+		handler    ValueHandlerFunc
+
+		expectedStatusCode  int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:                "no accept header",
+			handler:             okHandler,
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "application/json; charset=utf-8",
+			expectedBody:        "{\"title\":\"go\"}\n",
+		}, {
+			name:                "xml preferred by q-value",
+			accept:              "application/json;q=0.5, application/xml",
+			handler:             okHandler,
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "application/xml; charset=utf-8",
+			expectedBody:        "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<news><title>go</title></news>",
+		}, {
+			name:                "html template",
+			accept:              "text/html,application/xhtml+xml,*/*;q=0.8",
+			handler:             okHandler,
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "text/html; charset=utf-8",
+			expectedBody:        "<h1>go</h1>",
+		}, {
+			name:                "wildcard subtype",
+			accept:              "text/*",
+			handler:             okHandler,
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "text/html; charset=utf-8",
+			expectedBody:        "<h1>go</h1>",
+		}, {
+			name:                "zero q-value excludes type",
+			accept:              "text/plain, */*;q=0",
+			handler:             okHandler,
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "{go}",
+		}, {
+			name:                "zero q-value excludes type matched by wildcard",
+			negotiator:          NewNegotiator(JSON(), XML()),
+			accept:              "application/json;q=0, */*",
+			handler:             okHandler,
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "application/xml; charset=utf-8",
+			expectedBody:        "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<news><title>go</title></news>",
+		}, {
+			name:                "more specific range takes precedence",
+			accept:              "application/json;q=0.5, */*",
+			handler:             okHandler,
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "application/xml; charset=utf-8",
+			expectedBody:        "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<news><title>go</title></news>",
+		}, {
+			name:                "vendor type with structured syntax suffix",
+			accept:              "application/vnd.x.v2+xml",
+			handler:             okHandler,
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "application/xml; charset=utf-8",
+			expectedBody:        "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<news><title>go</title></news>",
+		}, {
+			name:                "exact type preferred over vendor type",
+			accept:              "application/vnd.x.v2+json;q=0.5, application/json;q=0, application/xml;q=0.1",
+			handler:             okHandler,
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "application/xml; charset=utf-8",
+			expectedBody:        "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<news><title>go</title></news>",
+		}, {
+			name:                "custom renderer content type",
+			negotiator:          NewNegotiator(png),
+			accept:              "image/*",
+			handler:             okHandler,
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "image/png",
+			expectedBody:        "png",
+		}, {
+			name:                "renderer error",
+			negotiator:          NewNegotiator(HTMLTemplate(failingTmpl, "news")),
+			handler:             okHandler,
+			expectedStatusCode:  http.StatusInternalServerError,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "Internal Server Error\n",
+		}, {
+			name:                "nothing acceptable",
+			accept:              "image/png",
+			handler:             okHandler,
+			expectedStatusCode:  http.StatusNotAcceptable,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "406 not acceptable\n",
+		}, {
+			name:                "handler error",
+			handler:             errHandler,
+			expectedStatusCode:  http.StatusInternalServerError,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "Internal Server Error\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/news", nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			w := httptest.NewRecorder()
+
+			n := negotiator
+			if tc.negotiator != nil {
+				n = tc.negotiator
+			}
+			n.Handle(tc.handler)(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+			body, _ := ioutil.ReadAll(resp.Body)
+
+			if tc.expectedStatusCode != resp.StatusCode {
+				t.Errorf("Expected status code '%d', but got '%d'", tc.expectedStatusCode, resp.StatusCode)
+			}
+			if h := resp.Header.Get("Content-Type"); tc.expectedContentType != h {
+				t.Errorf("Expected Content-Type '%s', but got '%s'", tc.expectedContentType, h)
+			}
+			if tc.expectedBody != string(body) {
+				t.Errorf("Expected body '%s', but got '%s'", tc.expectedBody, string(body))
+			}
+		})
+	}
+}
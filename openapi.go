@@ -0,0 +1,267 @@
+package route
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp/syntax"
+	"strings"
+)
+
+// Schema is JSON schema object used in OpenAPI document.
+type Schema map[string]interface{}
+
+// OpenAPIDocument is OpenAPI 3 document describing routes registered in router.
+type OpenAPIDocument struct {
+	OpenAPI string                           `json:"openapi"`
+	Info    OpenAPIInfo                      `json:"info"`
+	Paths   map[string]map[string]*Operation `json:"paths"`
+}
+
+// OpenAPIInfo holds general information about API.
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Operation describes single method of a path.
+type Operation struct {
+	OperationID string              `json:"operationId,omitempty"`
+	Summary     string              `json:"summary,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// Parameter describes path parameter taken from named group.
+type Parameter struct {
+	Name     string `json:"name"`
+	In       string `json:"in"`
+	Required bool   `json:"required"`
+	Schema   Schema `json:"schema"`
+}
+
+// RequestBody describes request body of an operation.
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes response of an operation.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds schema of given content type.
+type MediaType struct {
+	Schema Schema `json:"schema"`
+}
+
+// OpenAPIOptions configures generated OpenAPI document.
+type OpenAPIOptions struct {
+	Info OpenAPIInfo
+
+	// RequestSchema returns schema of JSON request body for route and method, nil means no body.
+	RequestSchema func(route RouteInfo, method string) Schema
+	// ResponseSchema returns schema of JSON response for route and method, nil means response without content.
+	ResponseSchema func(route RouteInfo, method string) Schema
+}
+
+var openAPIMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+}
+
+// PathParam is parameter of path template, taken from named group of route pattern.
+type PathParam struct {
+	Name string
+	// Pattern is regexp the value must match.
+	Pattern string
+	// Integer tells if pattern matches only digits.
+	Integer bool
+}
+
+// parseTemplate converts regexp pattern into path template, i.e. `^/(?P<pk>\d+)$` into `/{pk}`. Only literals,
+// anchors, named groups and optional parts (which are omitted) are supported.
+func parseTemplate(pattern string) (string, []PathParam, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", nil, err
+	}
+	var (
+		path   strings.Builder
+		params []PathParam
+	)
+	var walk func(re *syntax.Regexp) error
+	walk = func(re *syntax.Regexp) error {
+		switch re.Op {
+		case syntax.OpLiteral:
+			path.WriteString(string(re.Rune))
+		case syntax.OpBeginText, syntax.OpEndText, syntax.OpBeginLine, syntax.OpEndLine, syntax.OpEmptyMatch, syntax.OpQuest:
+		case syntax.OpConcat:
+			for _, sub := range re.Sub {
+				if err := walk(sub); err != nil {
+					return err
+				}
+			}
+		case syntax.OpCapture:
+			if re.Name == "" {
+				return walk(re.Sub[0])
+			}
+			path.WriteString("{" + re.Name + "}")
+			params = append(params, PathParam{
+				Name:    re.Name,
+				Pattern: re.Sub[0].String(),
+				Integer: isDigits(re.Sub[0]),
+			})
+		default:
+			return fmt.Errorf("unsupported expression %q in pattern %q", re.String(), pattern)
+		}
+		return nil
+	}
+	if err := walk(re); err != nil {
+		return "", nil, err
+	}
+	return path.String(), params, nil
+}
+
+func isDigits(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpPlus, syntax.OpStar, syntax.OpRepeat:
+		return isDigits(re.Sub[0])
+	case syntax.OpCharClass:
+		return len(re.Rune) == 2 && re.Rune[0] == '0' && re.Rune[1] == '9'
+	}
+	return false
+}
+
+// ParsePathTemplate converts chain of route patterns, from the outermost router to the innermost one, into path
+// template, i.e. `^/news` and `^/(?P<pk>\d+)$` into `/news/{pk}`. Only literals, anchors, named groups and
+// optional parts (which are omitted) are supported.
+func ParsePathTemplate(patterns ...string) (string, []PathParam, error) {
+	var (
+		path   string
+		params []PathParam
+	)
+	for _, pattern := range patterns {
+		p, ps, err := parseTemplate(pattern)
+		if err != nil {
+			return "", nil, err
+		}
+		path += p
+		params = append(params, ps...)
+	}
+	if path == "" {
+		path = "/"
+	}
+	return path, params, nil
+}
+
+func routeTemplate(routes []RouteInfo) (string, []PathParam, error) {
+	var patterns []string
+	for _, route := range routes {
+		patterns = append(patterns, route.Pattern)
+	}
+	return ParsePathTemplate(patterns...)
+}
+
+// GetRouteTemplate returns path template of routes matched so far, i.e. "/news/{pk}", or empty string when no route
+// was matched. Patterns which can't be converted into template are joined as they are.
+func GetRouteTemplate(r *http.Request) string {
+	state := getMatch(r)
+	if state == nil || len(state.routes) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for _, route := range state.routes {
+		b.WriteString(route.Template)
+	}
+	if b.Len() == 0 {
+		return "/"
+	}
+	return b.String()
+}
+
+// OpenAPI generates OpenAPI 3 document from routes registered in router and its sub routers. Routes which
+// patterns can't be converted into path template are skipped, routes accepting any method are documented as GET.
+func (r *RegexpRouter) OpenAPI(opts OpenAPIOptions) *OpenAPIDocument {
+	doc := &OpenAPIDocument{
+		OpenAPI: "3.0.3",
+		Info:    opts.Info,
+		Paths:   map[string]map[string]*Operation{},
+	}
+	r.Walk(func(route RouteInfo, parents []RouteInfo) error {
+		path, params, err := routeTemplate(appendRoute(parents, route))
+		if err != nil {
+			return nil
+		}
+		methods := route.Methods
+		if len(methods) == 0 {
+			methods = []string{http.MethodGet}
+		}
+		for _, method := range methods {
+			if !openAPIMethods[method] {
+				continue
+			}
+			if doc.Paths[path] == nil {
+				doc.Paths[path] = map[string]*Operation{}
+			}
+			doc.Paths[path][strings.ToLower(method)] = newOperation(route, method, len(methods) > 1, params, opts)
+		}
+		return nil
+	})
+	return doc
+}
+
+func newOperation(route RouteInfo, method string, multiple bool, params []PathParam, opts OpenAPIOptions) *Operation {
+	op := &Operation{
+		OperationID: route.Meta.Name,
+		Summary:     route.Meta.Description,
+		Tags:        route.Meta.Tags,
+		Responses:   map[string]Response{},
+	}
+	if op.OperationID != "" && multiple {
+		op.OperationID += "_" + strings.ToLower(method)
+	}
+	for _, param := range params {
+		schema := Schema{"type": "string", "pattern": "^(?:" + param.Pattern + ")$"}
+		if param.Integer {
+			schema = Schema{"type": "integer"}
+		}
+		op.Parameters = append(op.Parameters, Parameter{Name: param.Name, In: "path", Required: true, Schema: schema})
+	}
+	if opts.RequestSchema != nil {
+		if schema := opts.RequestSchema(route, method); schema != nil {
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]MediaType{"application/json": {Schema: schema}},
+			}
+		}
+	}
+	response := Response{Description: http.StatusText(http.StatusOK)}
+	if opts.ResponseSchema != nil {
+		if schema := opts.ResponseSchema(route, method); schema != nil {
+			response.Content = map[string]MediaType{"application/json": {Schema: schema}}
+		}
+	}
+	op.Responses["200"] = response
+	return op
+}
+
+// OpenAPIHandler returns handler serving OpenAPI document of given router as JSON, document is generated on
+// every request so it reflects current state of the router.
+func OpenAPIHandler(router *RegexpRouter, opts OpenAPIOptions) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(router.OpenAPI(opts))
+	}
+}
@@ -0,0 +1,169 @@
+package route
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestParseTemplate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		pattern string
+
+		expectedPath   string
+		expectedParams []PathParam
+		expectedError  bool
+	}{
+		{
+			name:         "literal",
+			pattern:      `^/news/$`,
+			expectedPath: "/news/",
+		}, {
+			name:         "integer param",
+			pattern:      `^/(?P<pk>\d+)/$`,
+			expectedPath: "/{pk}/",
+			expectedParams: []PathParam{
+				{Name: "pk", Pattern: `[0-9]+`, Integer: true},
+			},
+		}, {
+			name:         "many params",
+			pattern:      `^/(?P<pk>\d+),(?P<slug>[a-z\-_]+)\.html$`,
+			expectedPath: "/{pk},{slug}.html",
+			expectedParams: []PathParam{
+				{Name: "pk", Pattern: `[0-9]+`, Integer: true},
+				{Name: "slug", Pattern: `[\-_a-z]+`},
+			},
+		}, {
+			name:         "optional trailing slash",
+			pattern:      `^/news/?$`,
+			expectedPath: "/news",
+		}, {
+			name:          "unsupported expression",
+			pattern:       `^/(news|blog)$`,
+			expectedError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path, params, err := parseTemplate(tc.pattern)
+			if tc.expectedError != (err != nil) {
+				t.Fatalf("Unexpected error: '%v'", err)
+			}
+			if tc.expectedPath != path {
+				t.Errorf("Expected path '%s', got '%s'", tc.expectedPath, path)
+			}
+			if !reflect.DeepEqual(tc.expectedParams, params) {
+				t.Errorf("Expected params '%+v', got '%+v'", tc.expectedParams, params)
+			}
+		})
+	}
+}
+
+func TestOpenAPIHandler(t *testing.T) {
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+	newsRoutes := New().
+		AddWithMeta(`^/$`, noop, Meta{Name: "news-list", Tags: []string{"news"}}, http.MethodGet, http.MethodPost).
+		AddWithMeta(`^/(?P<pk>\d+),(?P<slug>[a-z]+)\.html$`, noop, Meta{Name: "news-detail", Description: "Single news"}, http.MethodGet)
+	routing := New().
+		Add(`^/news`, newsRoutes).
+		Add(`^/(news|blog)$`, noop)
+	routing.Add(`^/openapi\.json$`, OpenAPIHandler(routing, OpenAPIOptions{
+		Info: OpenAPIInfo{Title: "News", Version: "1.0"},
+		RequestSchema: func(route RouteInfo, method string) Schema {
+			if method == http.MethodPost {
+				return Schema{"type": "object"}
+			}
+			return nil
+		},
+		ResponseSchema: func(route RouteInfo, method string) Schema {
+			if route.Meta.Name == "news-detail" {
+				return Schema{"$ref": "#/components/schemas/News"}
+			}
+			return nil
+		},
+	}), http.MethodGet)
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/openapi.json", nil)
+	w := httptest.NewRecorder()
+	routing.ServeHTTP(w, req)
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected Content-Type 'application/json', got '%s'", ct)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	expected := map[string]interface{}{}
+	json.Unmarshal([]byte(`{
+		"openapi": "3.0.3",
+		"info": {"title": "News", "version": "1.0"},
+		"paths": {
+			"/news/": {
+				"get": {"operationId": "news-list_get", "tags": ["news"], "responses": {"200": {"description": "OK"}}},
+				"post": {
+					"operationId": "news-list_post",
+					"tags": ["news"],
+					"requestBody": {"required": true, "content": {"application/json": {"schema": {"type": "object"}}}},
+					"responses": {"200": {"description": "OK"}}
+				}
+			},
+			"/news/{pk},{slug}.html": {
+				"get": {
+					"operationId": "news-detail",
+					"summary": "Single news",
+					"parameters": [
+						{"name": "pk", "in": "path", "required": true, "schema": {"type": "integer"}},
+						{"name": "slug", "in": "path", "required": true, "schema": {"type": "string", "pattern": "^(?:[a-z]+)$"}}
+					],
+					"responses": {"200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/News"}}}}}
+				}
+			},
+			"/openapi.json": {
+				"get": {"responses": {"200": {"description": "OK"}}}
+			}
+		}
+	}`), &expected)
+	if !reflect.DeepEqual(expected, doc) {
+		t.Errorf("Expected document '%v', got '%v'", expected, doc)
+	}
+}
+
+func TestGetRouteTemplate(t *testing.T) {
+	var template string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		template = GetRouteTemplate(r)
+	}
+	routing := New().
+		Add(`^/news`, New().Add(`^/(?P<pk>\d+)$`, handler).Add(`^/(news|blog)$`, handler)).
+		Add(`^/api`, NewVersionRouter("v1").Add("v1", New().Add(`^/items$`, handler))).
+		Add(`^$`, handler)
+
+	testCases := []struct {
+		name string
+		path string
+
+		expectedTemplate string
+	}{
+		{name: "nested routers", path: "/news/12", expectedTemplate: "/news/{pk}"},
+		{name: "pattern without template", path: "/news/blog", expectedTemplate: "/news^/(news|blog)$"},
+		{name: "version from path", path: "/api/v1/items", expectedTemplate: "/api/v1/items"},
+		{name: "empty template", path: "", expectedTemplate: "/"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			template = ""
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+			req.URL.Path = tc.path
+
+			routing.ServeHTTP(httptest.NewRecorder(), req)
+
+			if tc.expectedTemplate != template {
+				t.Errorf("Expected template '%s', but got '%s'", tc.expectedTemplate, template)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package route
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+var errorRendererKey = "_error_renderer_"
+
+// ErrorRenderer writes error response with given status code and detail message.
+type ErrorRenderer func(w http.ResponseWriter, r *http.Request, status int, detail string)
+
+// TextErrors renders errors as plain text, it's used when no other renderer is set.
+func TextErrors(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	if detail == "" {
+		detail = http.StatusText(status)
+	}
+	http.Error(w, detail, status)
+}
+
+// Problem is RFC 9457 problem details object, Extensions are serialized as additional members.
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]interface{}
+}
+
+func (p Problem) MarshalJSON() ([]byte, error) {
+	members := map[string]interface{}{}
+	for k, v := range p.Extensions {
+		members[k] = v
+	}
+	members["type"] = p.Type
+	members["title"] = p.Title
+	members["status"] = p.Status
+	if p.Detail != "" {
+		members["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		members["instance"] = p.Instance
+	}
+	return json.Marshal(members)
+}
+
+// ProblemJSON returns ErrorRenderer writing application/problem+json responses. Optional extend function can
+// customize problem before it's written, i.e. set type or extension fields.
+func ProblemJSON(extend func(r *http.Request, p *Problem)) ErrorRenderer {
+	return func(w http.ResponseWriter, r *http.Request, status int, detail string) {
+		p := &Problem{
+			Type:     "about:blank",
+			Title:    http.StatusText(status),
+			Status:   status,
+			Detail:   detail,
+			Instance: r.URL.Path,
+		}
+		if extend != nil {
+			extend(r, p)
+		}
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(p)
+	}
+}
+
+// WithErrorRenderer returns request which errors will be rendered using given renderer. Routers do it for
+// requests they handle when their ErrorRenderer is set, it's useful when middlewares are used without router.
+func WithErrorRenderer(r *http.Request, renderer ErrorRenderer) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), errorRendererKey, renderer))
+}
+
+// RenderError writes error response using renderer of the request, or as plain text when none is set.
+func RenderError(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	renderer, ok := r.Context().Value(errorRendererKey).(ErrorRenderer)
+	if !ok {
+		renderer = TextErrors
+	}
+	renderer(w, r, status, detail)
+}
+
+func notFound(w http.ResponseWriter, r *http.Request) {
+	RenderError(w, r, http.StatusNotFound, "404 page not found")
+}
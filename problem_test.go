@@ -0,0 +1,77 @@
+package route
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProblemJSON(t *testing.T) {
+	errNotFound := errors.New("not found")
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+	failing := func(w http.ResponseWriter, r *http.Request) error {
+		return errNotFound
+	}
+	newsRoutes := New().
+		Add(`^/(?P<pk>\d+)$`, noop, http.MethodGet).
+		Add(`^/missing$`, failing).
+		MapError(errNotFound, http.StatusNotFound)
+	routing := New().Add(`^/news`, newsRoutes)
+	routing.ErrorRenderer = ProblemJSON(func(r *http.Request, p *Problem) {
+		p.Extensions = map[string]interface{}{"status": 1, "trace": "abc"}
+	})
+
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:               "not found",
+			method:             http.MethodGet,
+			path:               "/blog/",
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"detail":"404 page not found","instance":"/blog/","status":404,"title":"Not Found","trace":"abc","type":"about:blank"}` + "\n",
+		}, {
+			name:               "not found in sub router",
+			method:             http.MethodGet,
+			path:               "/news/abc",
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"detail":"404 page not found","instance":"/news/abc","status":404,"title":"Not Found","trace":"abc","type":"about:blank"}` + "\n",
+		}, {
+			name:               "method not allowed",
+			method:             http.MethodPost,
+			path:               "/news/12",
+			expectedStatusCode: http.StatusMethodNotAllowed,
+			expectedBody:       `{"detail":"Method Not Allowed","instance":"/news/12","status":405,"title":"Method Not Allowed","trace":"abc","type":"about:blank"}` + "\n",
+		}, {
+			name:               "handler error",
+			method:             http.MethodGet,
+			path:               "/news/missing",
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"detail":"Not Found","instance":"/news/missing","status":404,"title":"Not Found","trace":"abc","type":"about:blank"}` + "\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "http://example.com"+tc.path, nil)
+			w := httptest.NewRecorder()
+
+			routing.ServeHTTP(w, req)
+
+			if tc.expectedStatusCode != w.Code {
+				t.Errorf("Expected status code '%d', but got '%d'", tc.expectedStatusCode, w.Code)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "application/problem+json" {
+				t.Errorf("Expected Content-Type 'application/problem+json', but got '%s'", ct)
+			}
+			if tc.expectedBody != w.Body.String() {
+				t.Errorf("Expected body '%s', but got '%s'", tc.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package route
 import (
 	"net/http"
 	"regexp"
+	"sort"
 )
 
 var (
@@ -23,9 +24,14 @@ type route struct {
 	pattern        *regexp.Regexp
 	handler        Handler
 	allowedMethods map[string]struct{}
+	// anyMethod is set when route was added without methods, so it accepts any of defaultMethods.
+	anyMethod bool
+	meta      Meta
+	// routeInfo is computed once, when route is registered or changed, as it's read on every matched request.
+	routeInfo RouteInfo
 }
 
-func newRoute(pattern *regexp.Regexp, handler Handler, allowedMethods ...string) route {
+func newRoute(pattern *regexp.Regexp, handler Handler, meta Meta, allowedMethods ...string) route {
 	allowedMethodsMap := map[string]struct{}{}
 	if len(allowedMethods) == 0 {
 		allowedMethodsMap = defaultMethods
@@ -34,9 +40,40 @@ func newRoute(pattern *regexp.Regexp, handler Handler, allowedMethods ...string)
 			allowedMethodsMap[method] = struct{}{}
 		}
 	}
-	return route{
+	r := route{
 		pattern:        pattern,
 		handler:        handler,
 		allowedMethods: allowedMethodsMap,
+		anyMethod:      len(allowedMethods) == 0,
+		meta:           meta,
 	}
+	r.updateInfo()
+	return r
+}
+
+// updateInfo recomputes route info, it must be called after methods or metadata of route change.
+func (r *route) updateInfo() {
+	var methods []string
+	if !r.anyMethod {
+		methods = make([]string, 0, len(r.allowedMethods))
+		for method := range r.allowedMethods {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+	}
+	pattern := r.pattern.String()
+	template, _, err := parseTemplate(pattern)
+	if err != nil {
+		template = pattern
+	}
+	r.routeInfo = RouteInfo{
+		Pattern:  pattern,
+		Template: template,
+		Methods:  methods,
+		Meta:     r.meta,
+	}
+}
+
+func (r route) info() RouteInfo {
+	return r.routeInfo
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"regexp"
+	"strings"
 )
 
 var urlPathContextKey = struct{}{}
@@ -21,76 +22,173 @@ func (f HandlerFunc) handle(resp http.ResponseWriter, req *http.Request) {
 	f(resp, req)
 }
 
+// Middleware wraps handler with additional behaviour.
 type Middleware func(fn http.HandlerFunc) http.HandlerFunc
 
 // RegexpRouter
 type RegexpRouter struct {
 	routes      []route
 	middlewares []Middleware
+	cache       *matchCache
 	NotFound    func(w http.ResponseWriter, r *http.Request)
+
+	// ErrorRenderer renders 404 and 405 responses of router, and errors rendered by middlewares and handlers
+	// using RenderError. It's inherited by sub routers, plain text is used when not set.
+	ErrorRenderer ErrorRenderer
+
+	// ErrorHandler is called with errors returned by ErrorHandlerFunc handlers, when not set errors are answered
+	// with status code from ErrorStatus.
+	ErrorHandler  func(w http.ResponseWriter, r *http.Request, err error)
+	errorMappings []errorMapping
+
+	// Hooks are called on routing decisions of this router and its sub routers.
+	Hooks Hooks
 }
 
 func New() *RegexpRouter {
 	return &RegexpRouter{
-		NotFound: http.NotFound,
+		NotFound: notFound,
 	}
 }
 
 func (r *RegexpRouter) Add(pattern string, handler interface{}, allowedMethods ...string) *RegexpRouter {
-	var handlerFunc Handler
+	return r.AddWithMeta(pattern, handler, Meta{}, allowedMethods...)
+}
+
+// AddWithMeta works as Add, and attaches metadata to the route. Metadata can be read by middlewares using
+// GetRoute, and is exposed by Walk.
+func (r *RegexpRouter) AddWithMeta(pattern string, handler interface{}, meta Meta, allowedMethods ...string) *RegexpRouter {
+	r.routes = append(r.routes, newRoute(regexp.MustCompile(pattern), toHandler(handler), meta, allowedMethods...))
+	routesChanged()
 
+	return r
+}
+
+func toHandler(handler interface{}) Handler {
 	switch _handler := handler.(type) {
 	case func(http.ResponseWriter, *http.Request):
-		handlerFunc = HandlerFunc(_handler)
+		return HandlerFunc(_handler)
 	case http.HandlerFunc:
-		handlerFunc = HandlerFunc(_handler)
+		return HandlerFunc(_handler)
+	case func(http.ResponseWriter, *http.Request) error:
+		return ErrorHandlerFunc(_handler)
+	case ErrorHandlerFunc:
+		return _handler
 	case RegexpRouter:
-		handlerFunc = _handler
+		return _handler
 	case *RegexpRouter:
-		handlerFunc = _handler
+		return _handler
+	case *VersionRouter:
+		return _handler
 	default:
 		panic("Unknown handler param passed to RegexpRouter.Add")
 	}
-
-	r.routes = append(r.routes, newRoute(regexp.MustCompile(pattern), handlerFunc, allowedMethods...))
-
-	return r
 }
 
+// AddMiddleware adds middleware wrapping handlers of all routes. Each middleware wraps the ones added before it, so
+// the last one added runs first, use Chain to add several middlewares in the order they should run.
 func (r *RegexpRouter) AddMiddleware(mw Middleware) *RegexpRouter {
 	r.middlewares = append(r.middlewares, mw)
 	return r
 }
 
+// lookup finds index of first route matching urlPath, along with submatches and status telling if route wasn't
+// found or method isn't allowed.
+func (r RegexpRouter) lookup(method, urlPath string) (int, []string, int) {
+	for i, route := range r.routes {
+		if match := route.pattern.FindStringSubmatch(urlPath); match != nil {
+			if _, ok := route.allowedMethods[method]; !ok {
+				return i, nil, http.StatusMethodNotAllowed
+			}
+			return i, match, http.StatusOK
+		}
+	}
+	return -1, nil, http.StatusNotFound
+}
+
+// resolve returns route matching request along with submatches and url path left for sub router, resolution is
+// replayed from match cache when available.
+func (r RegexpRouter) resolve(req *http.Request, urlPath string) (*route, []string, string, int) {
+	state := getMatch(req)
+	if h, ok := state.replayHop(); ok && h.index < len(r.routes) {
+		return &r.routes[h.index], h.match, h.urlPath, http.StatusOK
+	}
+	i, match, status := r.lookup(req.Method, urlPath)
+	if status != http.StatusOK {
+		if i < 0 {
+			return nil, nil, urlPath, status
+		}
+		return &r.routes[i], nil, urlPath, status
+	}
+	route := &r.routes[i]
+	urlPath = route.pattern.ReplaceAllString(urlPath, "")
+	state.recordHop(hop{index: i, match: match, urlPath: urlPath})
+	return route, match, urlPath, status
+}
+
 func (r RegexpRouter) handle(rw http.ResponseWriter, req *http.Request) {
+	if r.ErrorRenderer != nil {
+		req = WithErrorRenderer(req, r.ErrorRenderer)
+	}
+	req = r.withHooks(req)
 	urlPath := req.Context().Value(urlPathContextKey).(string)
-	for _, route := range r.routes {
-		if route.pattern.MatchString(urlPath) {
-			if _, ok := route.allowedMethods[req.Method]; !ok {
-				http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
-				return
-			}
-			match := route.pattern.FindStringSubmatch(urlPath)
-			for i, name := range route.pattern.SubexpNames() {
-				if i != 0 {
-					SetParam(req, name, match[i])
-				}
-			}
-			urlPath = route.pattern.ReplaceAllString(urlPath, "")
-			req = req.WithContext(context.WithValue(req.Context(), urlPathContextKey, urlPath))
-			fn := route.handler.handle
-			for _, middleware := range r.middlewares {
-				fn = middleware(fn)
-			}
-			fn(rw, req)
+	route, match, urlPath, status := r.resolve(req, urlPath)
+	switch status {
+	case http.StatusNotFound:
+		onNotFound(req)
+		r.NotFound(rw, req)
+		return
+	case http.StatusMethodNotAllowed:
+		onMethodNotAllowed(req, route.info())
+		methodNotAllowed := func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Allow", strings.Join(route.info().Methods, ", "))
+			RenderError(w, req, http.StatusMethodNotAllowed, http.StatusText(http.StatusMethodNotAllowed))
+		}
+		if !isPreflight(req) {
+			methodNotAllowed(rw, req)
 			return
 		}
+		// CORS preflight is passed to middlewares, so they can answer it
+		addMatchedRoute(req, route.info())
+		r.wrap(methodNotAllowed)(rw, req)
+		return
 	}
-	r.NotFound(rw, req)
+	for i, name := range route.pattern.SubexpNames() {
+		if i != 0 {
+			SetParam(req, name, match[i])
+		}
+	}
+	addMatchedRoute(req, route.info())
+	if _, ok := route.handler.(matcher); !ok {
+		getMatch(req).commit()
+		onMatch(req, route.info())
+	}
+	req = req.WithContext(context.WithValue(req.Context(), urlPathContextKey, urlPath))
+	req = r.withErrorConfig(req)
+	r.wrap(route.handler.handle)(rw, req)
+}
+
+// wrap wraps fn with middlewares of router.
+func (r RegexpRouter) wrap(fn http.HandlerFunc) http.HandlerFunc {
+	for _, middleware := range r.middlewares {
+		fn = middleware(fn)
+	}
+	return fn
+}
+
+// isPreflight tells if request is CORS preflight request.
+func isPreflight(req *http.Request) bool {
+	return req.Method == http.MethodOptions && req.Header.Get("Origin") != "" &&
+		req.Header.Get("Access-Control-Request-Method") != ""
 }
 
 func (r RegexpRouter) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	initParams(req)
+	initMatch(req)
+	getMatch(req).root = r
+	if r.cache != nil {
+		r.cache.prepare(getMatch(req), cacheKey{host: req.Host, method: req.Method, path: req.URL.Path})
+	}
 	req = req.WithContext(context.WithValue(req.Context(), urlPathContextKey, req.URL.Path))
 	r.handle(rw, req)
 }
@@ -0,0 +1,154 @@
+// Package routetest provides assertion helpers for testing route matching of go-route routers.
+package routetest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Alkemic/go-route"
+)
+
+// Router is implemented by route.RegexpRouter and route.VersionRouter.
+type Router interface {
+	http.Handler
+	Match(req *http.Request) route.Match
+	Walk(fn route.WalkFunc) error
+}
+
+// Matches asserts that request is routed to a handler, and that given params (key, value pairs) were extracted.
+func Matches(t testing.TB, router Router, method, path string, params ...string) bool {
+	t.Helper()
+	if len(params)%2 != 0 {
+		t.Fatalf("Odd number of params passed to routetest.Matches: %v", params)
+	}
+	m := router.Match(httptest.NewRequest(method, path, nil))
+	if m.Status != http.StatusOK {
+		t.Errorf("Expected %s %s to match, but got %d %s%s", method, path, m.Status, http.StatusText(m.Status), describe(router, m, path))
+		return false
+	}
+	ok := true
+	for i := 0; i < len(params); i += 2 {
+		key, expected := params[i], params[i+1]
+		value, found := m.Params[key]
+		if !found {
+			t.Errorf("Expected %s %s to have param '%s', but it's missing%s", method, path, key, describe(router, m, path))
+			ok = false
+		} else if value != expected {
+			t.Errorf("Expected %s %s to have param '%s' equal '%s', but got '%s'%s", method, path, key, expected, value, describe(router, m, path))
+			ok = false
+		}
+	}
+	return ok
+}
+
+// NotFound asserts that no route matches request.
+func NotFound(t testing.TB, router Router, method, path string) bool {
+	t.Helper()
+	return hasStatus(t, router, method, path, http.StatusNotFound)
+}
+
+// MethodNotAllowed asserts that request matches a route which doesn't allow its method.
+func MethodNotAllowed(t testing.TB, router Router, method, path string) bool {
+	t.Helper()
+	return hasStatus(t, router, method, path, http.StatusMethodNotAllowed)
+}
+
+func hasStatus(t testing.TB, router Router, method, path string, status int) bool {
+	t.Helper()
+	m := router.Match(httptest.NewRequest(method, path, nil))
+	if m.Status != status {
+		t.Errorf("Expected %s %s to be %d %s, but got %d %s%s", method, path, status, http.StatusText(status),
+			m.Status, http.StatusText(m.Status), describe(router, m, path))
+		return false
+	}
+	return true
+}
+
+// Redirects asserts that serving request results in redirect to given location. Unlike other assertions, it
+// calls the matched handler.
+func Redirects(t testing.TB, router Router, method, path, location string) bool {
+	t.Helper()
+	req := httptest.NewRequest(method, path, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code < 300 || w.Code > 399 {
+		t.Errorf("Expected %s %s to redirect, but got status %d%s", method, path, w.Code, describe(router, router.Match(req), path))
+		return false
+	}
+	if got := w.Header().Get("Location"); got != location {
+		t.Errorf("Expected %s %s to redirect to '%s', but got '%s'", method, path, location, got)
+		return false
+	}
+	return true
+}
+
+func describe(router Router, m route.Match, path string) string {
+	if m.Status == http.StatusOK || m.Status == http.StatusMethodNotAllowed {
+		return "\nmatched route: " + chain(m.Routes)
+	}
+	nearest := candidates(router, path, 3)
+	if len(nearest) == 0 {
+		return ""
+	}
+	return "\nnearest routes:\n\t" + strings.Join(nearest, "\n\t")
+}
+
+func chain(routes []route.RouteInfo) string {
+	var patterns []string
+	for _, r := range routes {
+		patterns = append(patterns, r.Pattern)
+	}
+	return strings.Join(patterns, " -> ")
+}
+
+// candidates returns routes which literal prefix shares the longest common prefix with path.
+func candidates(router Router, path string, limit int) []string {
+	type candidate struct {
+		route string
+		score int
+	}
+	var found []candidate
+	router.Walk(func(r route.RouteInfo, parents []route.RouteInfo) error {
+		routes := append(append([]route.RouteInfo(nil), parents...), r)
+		if score := commonPrefix(literalPrefix(routes), path); score > 0 {
+			found = append(found, candidate{route: chain(routes), score: score})
+		}
+		return nil
+	})
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].score > found[j].score
+	})
+	var nearest []string
+	for i := 0; i < len(found) && i < limit; i++ {
+		nearest = append(nearest, found[i].route)
+	}
+	return nearest
+}
+
+func literalPrefix(routes []route.RouteInfo) string {
+	var prefix string
+	for _, r := range routes {
+		re, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			break
+		}
+		p, complete := re.LiteralPrefix()
+		prefix += p
+		if !complete {
+			break
+		}
+	}
+	return prefix
+}
+
+func commonPrefix(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,115 @@
+package routetest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Alkemic/go-route"
+)
+
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func newRouter() *route.RegexpRouter {
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+	newsRoutes := route.New().
+		Add(`^/$`, noop, http.MethodGet).
+		Add(`^/(?P<pk>\d+),(?P<slug>[a-z\-_]+)\.html$`, noop, http.MethodGet)
+	return route.New().
+		Add(`^/news`, newsRoutes).
+		Add(`^/old-news$`, func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, "/news/", http.StatusMovedPermanently)
+		})
+}
+
+func TestAssertions(t *testing.T) {
+	router := newRouter()
+
+	testCases := []struct {
+		name   string
+		assert func(t testing.TB) bool
+
+		expectedOk     bool
+		expectedErrors []string
+	}{
+		{
+			name: "matches with params",
+			assert: func(t testing.TB) bool {
+				return Matches(t, router, http.MethodGet, "/news/12,abc.html", "pk", "12", "slug", "abc")
+			},
+			expectedOk: true,
+		}, {
+			name: "matches with wrong param",
+			assert: func(t testing.TB) bool {
+				return Matches(t, router, http.MethodGet, "/news/12,abc.html", "pk", "13")
+			},
+			expectedErrors: []string{
+				"Expected GET /news/12,abc.html to have param 'pk' equal '13', but got '12'\nmatched route: ^/news -> ^/(?P<pk>\\d+),(?P<slug>[a-z\\-_]+)\\.html$",
+			},
+		}, {
+			name: "matches fails on unknown path",
+			assert: func(t testing.TB) bool {
+				return Matches(t, router, http.MethodGet, "/news/abc.html")
+			},
+			expectedErrors: []string{
+				"Expected GET /news/abc.html to match, but got 404 Not Found\nnearest routes:\n\t^/news -> ^/$\n\t^/news -> ^/(?P<pk>\\d+),(?P<slug>[a-z\\-_]+)\\.html$\n\t^/old-news$",
+			},
+		}, {
+			name: "not found",
+			assert: func(t testing.TB) bool {
+				return NotFound(t, router, http.MethodGet, "/blog/")
+			},
+			expectedOk: true,
+		}, {
+			name: "method not allowed",
+			assert: func(t testing.TB) bool {
+				return MethodNotAllowed(t, router, http.MethodPost, "/news/")
+			},
+			expectedOk: true,
+		}, {
+			name: "method not allowed fails on allowed method",
+			assert: func(t testing.TB) bool {
+				return MethodNotAllowed(t, router, http.MethodGet, "/news/")
+			},
+			expectedErrors: []string{
+				"Expected GET /news/ to be 405 Method Not Allowed, but got 200 OK\nmatched route: ^/news -> ^/$",
+			},
+		}, {
+			name: "redirects",
+			assert: func(t testing.TB) bool {
+				return Redirects(t, router, http.MethodGet, "/old-news", "/news/")
+			},
+			expectedOk: true,
+		}, {
+			name: "redirects fails on different location",
+			assert: func(t testing.TB) bool {
+				return Redirects(t, router, http.MethodGet, "/old-news", "/blog/")
+			},
+			expectedErrors: []string{
+				"Expected GET /old-news to redirect to '/blog/', but got '/news/'",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := &recorder{TB: t}
+			ok := tc.assert(rec)
+			if ok != tc.expectedOk {
+				t.Errorf("Expected assertion result to be %t, got %t", tc.expectedOk, ok)
+			}
+			if strings.Join(tc.expectedErrors, "\n") != strings.Join(rec.errors, "\n") {
+				t.Errorf("Expected errors '%v', got '%v'", tc.expectedErrors, rec.errors)
+			}
+		})
+	}
+}
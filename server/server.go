@@ -0,0 +1,181 @@
+// Package server runs http.Server serving router with sensible timeouts and graceful shutdown.
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/Alkemic/go-route"
+)
+
+// Server serves handler, usually *route.RegexpRouter, and drains in-flight requests on shutdown.
+type Server struct {
+	// Addr is "host:port" TCP address or "unix:/path/to/socket".
+	Addr    string
+	Handler http.Handler
+
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	// WriteTimeout limits time of writing whole response, set it to 0 when serving long lived streams.
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+
+	// DrainTimeout is deadline for in-flight requests to finish after shutdown is requested. Contexts of requests
+	// are canceled when draining starts, so long lived handlers, i.e. event streams, can return.
+	DrainTimeout time.Duration
+	// ReadinessGrace is time between marking server as not ready and draining it, so load balancers can notice.
+	ReadinessGrace time.Duration
+
+	// CertFile and KeyFile enable TLS when set.
+	CertFile string
+	KeyFile  string
+
+	Logger *log.Logger
+
+	ready int32
+}
+
+// New returns Server listening on addr with default timeouts.
+func New(addr string, handler http.Handler) *Server {
+	return &Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadTimeout:       15 * time.Second,
+		ReadHeaderTimeout: 5 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+		DrainTimeout:      30 * time.Second,
+	}
+}
+
+// Ready tells if server accepts requests, it's false before serving starts and after shutdown was requested.
+func (s *Server) Ready() bool {
+	return atomic.LoadInt32(&s.ready) == 1
+}
+
+// ReadinessHandler answers 200 while server is ready and 503 otherwise.
+func (s *Server) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.Ready() {
+		route.RenderError(w, r, http.StatusServiceUnavailable, "503 service unavailable")
+		return
+	}
+	w.Write([]byte("ok"))
+}
+
+// ListenAndServe serves until SIGINT or SIGTERM is received, then drains in-flight requests. Signals are no longer
+// caught while draining, so the second one terminates the process.
+func (s *Server) ListenAndServe() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	go func() {
+		select {
+		case sig := <-signals:
+			signal.Stop(signals)
+			s.logf("Received %s, shutting down", sig)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return s.Run(ctx)
+}
+
+// Run serves until ctx is done, then drains in-flight requests.
+func (s *Server) Run(ctx context.Context) error {
+	ln, err := s.Listen()
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Listen opens listener on Addr, "unix:" prefix means Unix socket.
+func (s *Server) Listen() (net.Listener, error) {
+	if strings.HasPrefix(s.Addr, "unix:") {
+		return net.Listen("unix", strings.TrimPrefix(s.Addr, "unix:"))
+	}
+	addr := s.Addr
+	if addr == "" {
+		addr = ":http"
+		if s.CertFile != "" {
+			addr = ":https"
+		}
+	}
+	return net.Listen("tcp", addr)
+}
+
+// Serve serves on ln until ctx is done, then marks server as not ready, waits ReadinessGrace and drains in-flight
+// requests for up to DrainTimeout.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	// http.Server.Shutdown doesn't cancel contexts of in-flight requests, streams would hold the drain open
+	baseCtx, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
+	srv := &http.Server{
+		Handler:           s.Handler,
+		ReadTimeout:       s.ReadTimeout,
+		ReadHeaderTimeout: s.ReadHeaderTimeout,
+		WriteTimeout:      s.WriteTimeout,
+		IdleTimeout:       s.IdleTimeout,
+		ErrorLog:          s.Logger,
+		BaseContext: func(net.Listener) context.Context {
+			return baseCtx
+		},
+	}
+	srv.RegisterOnShutdown(cancelRequests)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if s.CertFile != "" || s.KeyFile != "" {
+			serveErr <- srv.ServeTLS(ln, s.CertFile, s.KeyFile)
+		} else {
+			serveErr <- srv.Serve(ln)
+		}
+	}()
+	atomic.StoreInt32(&s.ready, 1)
+	s.logf("Listening on %s", ln.Addr())
+
+	select {
+	case err := <-serveErr:
+		atomic.StoreInt32(&s.ready, 0)
+		return err
+	case <-ctx.Done():
+	}
+
+	atomic.StoreInt32(&s.ready, 0)
+	if s.ReadinessGrace > 0 {
+		time.Sleep(s.ReadinessGrace)
+	}
+	drainCtx := context.Background()
+	if s.DrainTimeout > 0 {
+		var cancel context.CancelFunc
+		drainCtx, cancel = context.WithTimeout(drainCtx, s.DrainTimeout)
+		defer cancel()
+	}
+	if err := srv.Shutdown(drainCtx); err != nil {
+		srv.Close()
+		return err
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (s *Server) logf(format string, v ...interface{}) {
+	if s.Logger != nil {
+		s.Logger.Printf(format, v...)
+	} else {
+		log.Printf(format, v...)
+	}
+}
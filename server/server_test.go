@@ -0,0 +1,229 @@
+package server
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Alkemic/go-route"
+	"github.com/Alkemic/go-route/sse"
+)
+
+func TestServerDrains(t *testing.T) {
+	started := make(chan struct{})
+	routing := route.New().Add(`^/slow$`, func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte("done"))
+	})
+	srv := New("127.0.0.1:0", routing)
+	srv.ReadinessGrace = 10 * time.Millisecond
+	srv.Logger = log.New(ioutil.Discard, "", 0)
+	ln, err := srv.Listen()
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if srv.Ready() {
+		t.Error("Expected server not to be ready before serving")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- srv.Serve(ctx, ln)
+	}()
+
+	responses := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String() + "/slow")
+		if err != nil {
+			responses <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(resp.Body)
+		responses <- string(body)
+	}()
+	<-started
+	if !srv.Ready() {
+		t.Error("Expected server to be ready while serving")
+	}
+	cancel()
+
+	if body := <-responses; body != "done" {
+		t.Errorf("Expected in-flight request to finish with 'done', but got '%s'", body)
+	}
+	if err := <-served; err != nil {
+		t.Errorf("Unexpected error: '%v'", err)
+	}
+	if srv.Ready() {
+		t.Error("Expected server not to be ready after shutdown")
+	}
+}
+
+func TestServerDrainTimeout(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	routing := route.New().Add(`^/stuck$`, func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+	srv := New("127.0.0.1:0", routing)
+	srv.DrainTimeout = 10 * time.Millisecond
+	srv.Logger = log.New(ioutil.Discard, "", 0)
+	ln, err := srv.Listen()
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- srv.Serve(ctx, ln)
+	}()
+	go http.Get("http://" + ln.Addr().String() + "/stuck")
+	<-started
+	cancel()
+
+	if err := <-served; err != context.DeadlineExceeded {
+		t.Errorf("Expected error '%v', but got '%v'", context.DeadlineExceeded, err)
+	}
+}
+
+func TestServerDrainsStreams(t *testing.T) {
+	routing := route.New().Add(`^/events$`, sse.HandlerWithHeartbeat(func(r *http.Request, events chan<- sse.Event) {
+		select {
+		case events <- sse.Event{Data: "hello"}:
+		case <-r.Context().Done():
+			return
+		}
+		<-r.Context().Done()
+	}, 0))
+	srv := New("127.0.0.1:0", routing)
+	srv.DrainTimeout = 5 * time.Second
+	srv.Logger = log.New(ioutil.Discard, "", 0)
+	ln, err := srv.Listen()
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- srv.Serve(ctx, ln)
+	}()
+
+	resp, err := http.Get("http://" + ln.Addr().String() + "/events")
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	defer resp.Body.Close()
+	first := make([]byte, len("data: hello"))
+	if _, err := io.ReadFull(resp.Body, first); err != nil || string(first) != "data: hello" {
+		t.Fatalf("Expected stream to start with 'data: hello', but got '%s' (%v)", first, err)
+	}
+	start := time.Now()
+	cancel()
+
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("Unexpected error: '%v'", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Expected open stream not to hold the drain, but it took '%s'", elapsed)
+		}
+	case <-time.After(srv.DrainTimeout):
+		t.Error("Expected server to drain before DrainTimeout")
+	}
+}
+
+func TestServerUnixSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "server")
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "server.sock")
+
+	srv := New("unix:"+socket, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("unix"))
+	}))
+	srv.Logger = log.New(ioutil.Discard, "", 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	ln, err := srv.Listen()
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	served := make(chan error, 1)
+	go func() {
+		served <- srv.Serve(ctx, ln)
+	}()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}}
+	resp, err := client.Get("http://unix/")
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "unix" {
+		t.Errorf("Expected body 'unix', but got '%s'", body)
+	}
+
+	cancel()
+	if err := <-served; err != nil {
+		t.Errorf("Unexpected error: '%v'", err)
+	}
+}
+
+func TestReadinessHandler(t *testing.T) {
+	srv := New("", nil)
+	testCases := []struct {
+		name  string
+		ready int32
+
+		expectedStatusCode  int
+		expectedContentType string
+	}{
+		{
+			name:                "not ready",
+			ready:               0,
+			expectedStatusCode:  http.StatusServiceUnavailable,
+			expectedContentType: "application/problem+json",
+		}, {
+			name:                "ready",
+			ready:               1,
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "text/plain; charset=utf-8",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv.ready = tc.ready
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/ready", nil)
+			req = route.WithErrorRenderer(req, route.ProblemJSON(nil))
+			w := httptest.NewRecorder()
+
+			srv.ReadinessHandler(w, req)
+
+			if tc.expectedStatusCode != w.Code {
+				t.Errorf("Expected status code '%d', but got '%d'", tc.expectedStatusCode, w.Code)
+			}
+			if contentType := w.Header().Get("Content-Type"); tc.expectedContentType != contentType {
+				t.Errorf("Expected content type '%s', but got '%s'", tc.expectedContentType, contentType)
+			}
+		})
+	}
+}
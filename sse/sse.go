@@ -0,0 +1,134 @@
+// Package sse implements handler streaming Server-Sent Events to clients.
+package sse
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Alkemic/go-route"
+)
+
+// DefaultHeartbeat is interval of heartbeat comments sent by Handler.
+const DefaultHeartbeat = 15 * time.Second
+
+// Event is single message sent to client, empty fields are omitted.
+type Event struct {
+	ID    string
+	Event string
+	Data  string
+	// Retry tells client how long to wait before reconnecting.
+	Retry time.Duration
+}
+
+// WriteTo writes event in text/event-stream format, multi line data is sent as multiple data fields.
+func (e Event) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	if e.ID != "" {
+		b.WriteString("id: " + clean(e.ID) + "\n")
+	}
+	if e.Event != "" {
+		b.WriteString("event: " + clean(e.Event) + "\n")
+	}
+	if e.Retry > 0 {
+		fmt.Fprintf(&b, "retry: %d\n", e.Retry.Milliseconds())
+	}
+	if e.Data != "" || b.Len() == 0 {
+		for _, line := range strings.Split(strings.ReplaceAll(e.Data, "\r\n", "\n"), "\n") {
+			b.WriteString("data: " + line + "\n")
+		}
+	}
+	b.WriteString("\n")
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// clean removes characters that would end the field.
+func clean(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "", "\x00", "").Replace(s)
+}
+
+// Source produces events for single client until it returns. It must stop sending when request context is done.
+type Source func(r *http.Request, events chan<- Event)
+
+// LastEventID returns ID of the last event received by reconnecting client.
+func LastEventID(r *http.Request) string {
+	return r.Header.Get("Last-Event-ID")
+}
+
+// Handler returns handler streaming events produced by source, heartbeat comments are sent every DefaultHeartbeat.
+func Handler(source Source) http.HandlerFunc {
+	return HandlerWithHeartbeat(source, DefaultHeartbeat)
+}
+
+// HandlerWithHeartbeat returns handler streaming events produced by source, heartbeat comments keeping idle
+// connection open are sent at given interval, zero disables them. Stream ends when source returns or client
+// disconnects.
+func HandlerWithHeartbeat(source Source, heartbeat time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		flusher := findFlusher(w)
+		if flusher == nil {
+			route.RenderError(w, r, http.StatusInternalServerError, "500 streaming unsupported")
+			return
+		}
+
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		r = r.WithContext(ctx)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		events := make(chan Event)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			source(r, events)
+		}()
+
+		var tick <-chan time.Time
+		if heartbeat > 0 {
+			ticker := time.NewTicker(heartbeat)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			case event := <-events:
+				if _, err := event.WriteTo(w); err != nil {
+					return
+				}
+				flusher.Flush()
+			case <-tick:
+				if _, err := io.WriteString(w, ":\n\n"); err != nil {
+					return
+				}
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+// findFlusher returns http.Flusher implemented by w or one of writers it wraps.
+func findFlusher(w http.ResponseWriter) http.Flusher {
+	for {
+		if flusher, ok := w.(http.Flusher); ok {
+			return flusher
+		}
+		unwrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return nil
+		}
+		w = unwrapper.Unwrap()
+	}
+}
@@ -0,0 +1,111 @@
+package sse
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventWriteTo(t *testing.T) {
+	testCases := []struct {
+		name  string
+		event Event
+
+		expected string
+	}{
+		{
+			name:     "data",
+			event:    Event{Data: "foo"},
+			expected: "data: foo\n\n",
+		}, {
+			name:     "all fields",
+			event:    Event{ID: "7", Event: "update", Data: "foo\nbar", Retry: 3 * time.Second},
+			expected: "id: 7\nevent: update\nretry: 3000\ndata: foo\ndata: bar\n\n",
+		}, {
+			name:     "retry only",
+			event:    Event{Retry: time.Second},
+			expected: "retry: 1000\n\n",
+		}, {
+			name:     "new lines in id",
+			event:    Event{ID: "1\n2", Data: "foo"},
+			expected: "id: 12\ndata: foo\n\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var b strings.Builder
+			if _, err := tc.event.WriteTo(&b); err != nil {
+				t.Fatalf("Unexpected error: '%v'", err)
+			}
+			if tc.expected != b.String() {
+				t.Errorf("Expected '%q', but got '%q'", tc.expected, b.String())
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	handler := Handler(func(r *http.Request, events chan<- Event) {
+		events <- Event{ID: LastEventID(r) + "1", Data: "foo"}
+		events <- Event{ID: LastEventID(r) + "2", Event: "update", Data: "bar"}
+	})
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/events", nil)
+	req.Header.Set("Last-Event-ID", "4")
+	w := httptest.NewRecorder()
+
+	handler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code '%d', but got '%d'", http.StatusOK, w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Expected Content-Type 'text/event-stream', but got '%s'", ct)
+	}
+	if !w.Flushed {
+		t.Error("Expected response to be flushed")
+	}
+	expected := "id: 41\ndata: foo\n\nid: 42\nevent: update\ndata: bar\n\n"
+	if w.Body.String() != expected {
+		t.Errorf("Expected body '%q', but got '%q'", expected, w.Body.String())
+	}
+}
+
+func TestHandlerHeartbeat(t *testing.T) {
+	stopped := make(chan struct{})
+	handler := HandlerWithHeartbeat(func(r *http.Request, events chan<- Event) {
+		<-r.Context().Done()
+		close(stopped)
+	}, 5*time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/events", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	handler(w, req)
+
+	if !strings.HasPrefix(w.Body.String(), ":\n\n") {
+		t.Errorf("Expected heartbeat comments, but got '%q'", w.Body.String())
+	}
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Error("Expected source to be stopped when client disconnects")
+	}
+}
+
+type plainWriter struct{ http.ResponseWriter }
+
+func TestHandlerWithoutFlusher(t *testing.T) {
+	handler := Handler(func(r *http.Request, events chan<- Event) {})
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/events", nil)
+	w := httptest.NewRecorder()
+
+	handler(plainWriter{w}, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status code '%d', but got '%d'", http.StatusInternalServerError, w.Code)
+	}
+}
@@ -0,0 +1,156 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	versionKey = "_version_"
+
+	versionPathPattern  = regexp.MustCompile(`^/(v\d+)(/|$)`)
+	versionMediaPattern = regexp.MustCompile(`^application/vnd\.[^.]+\.(v\d+)(\+|$)`)
+	versionNamePattern  = regexp.MustCompile(`^v\d+$`)
+)
+
+type apiVersion struct {
+	router      *RegexpRouter
+	deprecated  bool
+	deprecation time.Time
+	sunset      time.Time
+}
+
+// VersionRouter dispatches requests between routers serving different versions of API. Version is taken from
+// path prefix (i.e. /v2/news), from vendor media type in Accept header (i.e. application/vnd.x.v2+json) or from
+// custom header, in that order. Requests without any version are passed to the default version.
+type VersionRouter struct {
+	versions       map[string]*apiVersion
+	defaultVersion string
+
+	// Header is name of custom header holding requested version, i.e. "X-API-Version: 2".
+	Header   string
+	NotFound func(w http.ResponseWriter, r *http.Request)
+}
+
+// NewVersionRouter returns new VersionRouter, unversioned requests will be served by defaultVersion.
+func NewVersionRouter(defaultVersion string) *VersionRouter {
+	return &VersionRouter{
+		versions:       map[string]*apiVersion{},
+		defaultVersion: defaultVersion,
+		Header:         "X-API-Version",
+		NotFound:       notFound,
+	}
+}
+
+// Add registers router serving given version, version must be in form of "v<number>", i.e. "v2".
+func (v *VersionRouter) Add(version string, router *RegexpRouter) *VersionRouter {
+	if !versionNamePattern.MatchString(version) {
+		panic(fmt.Sprintf("Invalid version name %q passed to VersionRouter.Add", version))
+	}
+	v.versions[version] = &apiVersion{router: router}
+	routesChanged()
+	return v
+}
+
+// Deprecate marks version as retired, responses served by it will carry Deprecation and Sunset headers.
+// Zero deprecation time is sent as "true", zero sunset time omits Sunset header.
+func (v *VersionRouter) Deprecate(version string, deprecation, sunset time.Time) *VersionRouter {
+	apiVersion, ok := v.versions[version]
+	if !ok {
+		panic(fmt.Sprintf("Unknown version %q passed to VersionRouter.Deprecate", version))
+	}
+	apiVersion.deprecated = true
+	apiVersion.deprecation = deprecation
+	apiVersion.sunset = sunset
+	return v
+}
+
+func (v *VersionRouter) headerVersion(req *http.Request) string {
+	for _, accept := range strings.Split(req.Header.Get("Accept"), ",") {
+		mediaType := strings.TrimSpace(strings.SplitN(accept, ";", 2)[0])
+		if match := versionMediaPattern.FindStringSubmatch(mediaType); match != nil {
+			return match[1]
+		}
+	}
+	if version := strings.TrimSpace(req.Header.Get(v.Header)); version != "" {
+		if !strings.HasPrefix(version, "v") {
+			version = "v" + version
+		}
+		return version
+	}
+	return ""
+}
+
+// selectVersion returns requested version name, url path left after stripping version prefix, and indicator
+// if version was taken from path.
+func (v *VersionRouter) selectVersion(req *http.Request, urlPath string) (string, string, bool) {
+	if match := versionPathPattern.FindStringSubmatch(urlPath); match != nil {
+		return match[1], urlPath[len(match[1])+1:], true
+	}
+	if name := v.headerVersion(req); name != "" {
+		return name, urlPath, false
+	}
+	return v.defaultVersion, urlPath, false
+}
+
+// resolve works as selectVersion, but replays resolution from match cache when available. Versions selected by
+// headers aren't cacheable.
+func (v *VersionRouter) resolve(req *http.Request, urlPath string) (string, string, bool) {
+	state := getMatch(req)
+	if h, ok := state.replayHop(); ok {
+		return h.version, h.urlPath, true
+	}
+	name, urlPath, fromPath := v.selectVersion(req, urlPath)
+	if fromPath {
+		state.recordHop(hop{version: name, urlPath: urlPath})
+	} else {
+		state.markUncacheable()
+	}
+	return name, urlPath, fromPath
+}
+
+func (v *VersionRouter) handle(rw http.ResponseWriter, req *http.Request) {
+	urlPath := req.Context().Value(urlPathContextKey).(string)
+	name, urlPath, fromPath := v.resolve(req, urlPath)
+	version, ok := v.versions[name]
+	if !ok {
+		onNotFound(req)
+		v.NotFound(rw, req)
+		return
+	}
+	if fromPath {
+		addMatchedRoute(req, versionRoute(name))
+	}
+	if version.deprecated {
+		if version.deprecation.IsZero() {
+			rw.Header().Set("Deprecation", "true")
+		} else {
+			rw.Header().Set("Deprecation", fmt.Sprintf("@%d", version.deprecation.Unix()))
+		}
+		if !version.sunset.IsZero() {
+			rw.Header().Set("Sunset", version.sunset.UTC().Format(http.TimeFormat))
+		}
+	}
+
+	ctx := context.WithValue(req.Context(), versionKey, name)
+	ctx = context.WithValue(ctx, urlPathContextKey, urlPath)
+	version.router.handle(rw, req.WithContext(ctx))
+}
+
+func (v *VersionRouter) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	initParams(req)
+	initMatch(req)
+	getMatch(req).root = v
+	req = req.WithContext(context.WithValue(req.Context(), urlPathContextKey, req.URL.Path))
+	v.handle(rw, req)
+}
+
+// GetVersion returns API version selected by VersionRouter, will be empty if request wasn't dispatched by it.
+func GetVersion(r *http.Request) string {
+	version, _ := r.Context().Value(versionKey).(string)
+	return version
+}
@@ -0,0 +1,127 @@
+package route
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestVersionRouter(t *testing.T) {
+	versionHandler := func(name string) func(rw http.ResponseWriter, req *http.Request) {
+		return func(rw http.ResponseWriter, req *http.Request) {
+			fmt.Fprintf(rw, "%s %s %s", name, GetVersion(req), GetParams(req)["pk"])
+		}
+	}
+	v1 := New().Add(`^/news/(?P<pk>\d+)$`, versionHandler("v1"))
+	v2 := New().Add(`^/news/(?P<pk>\d+)$`, versionHandler("v2"))
+	sunset := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	versions := NewVersionRouter("v2").
+		Add("v1", v1).
+		Add("v2", v2).
+		Deprecate("v1", time.Unix(1700000000, 0), sunset)
+	routing := New().Add(`^/api`, versions)
+
+	testCases := []struct {
+		name    string
+		path    string
+		headers map[string]string
+
+		expectedStatusCode  int
+		expectedBody        string
+		expectedDeprecation string
+		expectedSunset      string
+	}{
+		{
+			name:               "default version",
+			path:               "/api/news/12",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "v2 v2 12",
+		}, {
+			name:                "version from path",
+			path:                "/api/v1/news/12",
+			expectedStatusCode:  http.StatusOK,
+			expectedBody:        "v1 v1 12",
+			expectedDeprecation: "@1700000000",
+			expectedSunset:      "Tue, 01 Jan 2030 00:00:00 GMT",
+		}, {
+			name:                "version from accept header",
+			path:                "/api/news/12",
+			headers:             map[string]string{"Accept": "text/html, application/vnd.x.v1+json;q=0.9"},
+			expectedStatusCode:  http.StatusOK,
+			expectedBody:        "v1 v1 12",
+			expectedDeprecation: "@1700000000",
+			expectedSunset:      "Tue, 01 Jan 2030 00:00:00 GMT",
+		}, {
+			name:               "version from custom header",
+			path:               "/api/news/12",
+			headers:            map[string]string{"X-API-Version": "2"},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "v2 v2 12",
+		}, {
+			name:               "path takes precedence over header",
+			path:               "/api/v2/news/12",
+			headers:            map[string]string{"X-API-Version": "v1"},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "v2 v2 12",
+		}, {
+			name:               "unknown version",
+			path:               "/api/v3/news/12",
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       "404 page not found\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "http://example.com"+tc.path, nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+
+			routing.ServeHTTP(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+			body, _ := ioutil.ReadAll(resp.Body)
+
+			if tc.expectedStatusCode != resp.StatusCode {
+				t.Errorf("Expected status code '%d', but got '%d'", tc.expectedStatusCode, resp.StatusCode)
+			}
+			if tc.expectedBody != string(body) {
+				t.Errorf("Expected body '%s', but got '%s'", tc.expectedBody, string(body))
+			}
+			if h := resp.Header.Get("Deprecation"); tc.expectedDeprecation != h {
+				t.Errorf("Expected Deprecation header '%s', but got '%s'", tc.expectedDeprecation, h)
+			}
+			if h := resp.Header.Get("Sunset"); tc.expectedSunset != h {
+				t.Errorf("Expected Sunset header '%s', but got '%s'", tc.expectedSunset, h)
+			}
+		})
+	}
+}
+
+func TestVersionRouterNegotiation(t *testing.T) {
+	negotiator := NewNegotiator(JSON())
+	v2 := New().Add(`^/news$`, negotiator.Handle(func(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+		return map[string]string{"version": GetVersion(r)}, nil
+	}))
+	routing := New().Add(`^/api`, NewVersionRouter("v1").Add("v1", New()).Add("v2", v2))
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/api/news", nil)
+	req.Header.Set("Accept", "application/vnd.x.v2+json")
+	w := httptest.NewRecorder()
+
+	routing.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code '%d', but got '%d'", http.StatusOK, w.Code)
+	}
+	if contentType := w.Header().Get("Content-Type"); contentType != "application/json; charset=utf-8" {
+		t.Errorf("Expected content type 'application/json; charset=utf-8', but got '%s'", contentType)
+	}
+	if body := w.Body.String(); body != "{\"version\":\"v2\"}\n" {
+		t.Errorf("Expected body '{\"version\":\"v2\"}\n', but got '%s'", body)
+	}
+}